@@ -1,19 +1,24 @@
 package component
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
-	"github.com/defenseunicorns/go-oscal/src/pkg/uuid"
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
-	"github.com/oscal-compass/oscal-sdk-go/extensions"
 	"github.com/oscal-compass/oscal-sdk-go/models"
 	"github.com/ossf/gemara/layer2"
 	"github.com/ossf/gemara/layer3"
 	"github.com/ossf/gemara/layer4"
 
+	"github.com/jpower432/gemara2oscal/controls"
 	"github.com/jpower432/gemara2oscal/internal/utils"
+	"github.com/jpower432/gemara2oscal/normalize"
+	"github.com/jpower432/gemara2oscal/vocabulary"
 )
 
 // DefinitionBuilder constructs an OSCAL Component Definition from Gemara
@@ -21,65 +26,145 @@ import (
 type DefinitionBuilder struct {
 	title               string
 	version             string
+	uuids               utils.UUIDGenerator
+	reproducibility     utils.Reproducibility
+	normalizers         normalize.Table
+	orders              map[string]ParameterOrder
+	catalogs            map[string]layer2.Catalog
 	targetComponents    map[string]oscalTypes.DefinedComponent
+	targetOrder         []string
 	validationComponent []oscalTypes.DefinedComponent
+	diagnostics         []controls.Diagnostic
+	strict              bool
+	existing            *oscalTypes.ComponentDefinition
+	mappingMetadata     MappingMetadataFunc
+	minimumStrength     int
+	vocabulary          vocabulary.Vocabulary
+	sources             SourceResolver
 }
 
-func NewDefinitionBuilder(title, version string) *DefinitionBuilder {
+// NewDefinitionBuilder returns a DefinitionBuilder for a component definition with the given
+// title and version. Components, control implementation sets and implemented requirements are
+// always emitted in a stable order.
+func NewDefinitionBuilder(title, version string, opts ...Option) *DefinitionBuilder {
+	options := options{vocabulary: vocabulary.Trestle}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	var diagnostics []controls.Diagnostic
+	if options.minimumStrength > 0 && options.mappingMetadata == nil {
+		diagnostics = append(diagnostics, controls.Diagnostic{
+			Severity: controls.SeverityError,
+			Location: title,
+			Message:  "minimum mapping strength is set without mapping metadata, so no mapping is excluded",
+		})
+	}
 	return &DefinitionBuilder{
 		title:            title,
 		version:          version,
+		uuids:            options.UUIDs(),
+		reproducibility:  options.Reproducibility,
+		normalizers:      options.normalizers,
+		orders:           options.orders,
+		strict:           options.strict,
+		mappingMetadata:  options.mappingMetadata,
+		minimumStrength:  options.minimumStrength,
+		vocabulary:       options.vocabulary,
+		sources:          options.sources,
+		catalogs:         make(map[string]layer2.Catalog),
 		targetComponents: make(map[string]oscalTypes.DefinedComponent),
+		diagnostics:      diagnostics,
 	}
 }
 
-func (c *DefinitionBuilder) AddTargetComponent(targetComponent, componentType string, catalog layer2.Catalog) *DefinitionBuilder {
+// AddTargetComponent adds a component with a rule for each assessment requirement of the Layer 2 catalog
+// and implemented requirements for the guideline mappings. TargetOptions limit the rules to the
+// applicable requirements, families and controls. The filter is recorded as component props. The control
+// implementation set sources are resolved with the SourceResolver (see WithSourceResolver).
+func (c *DefinitionBuilder) AddTargetComponent(targetComponent, componentType string, catalog layer2.Catalog, opts ...TargetOption) *DefinitionBuilder {
+	c.diagnostics = append(c.diagnostics, c.catalogDiagnostics(catalog)...)
+	filter, filterDiagnostics := newFilter(targetComponent, catalog, opts)
+	c.diagnostics = append(c.diagnostics, filterDiagnostics...)
 	mappingSet := make(map[string]oscalTypes.ControlImplementationSet)
+	sources := make(map[string]Source)
 	for _, mappingRef := range catalog.Metadata.MappingReferences {
+		sources[mappingRef.Id] = c.resolveSource(catalog, mappingRef)
 		mappingSet[mappingRef.Id] = oscalTypes.ControlImplementationSet{
-			UUID:        uuid.NewUUID(),
+			UUID:        c.uuids.Generate(targetComponent, catalog.Metadata.Id, mappingRef.Id),
 			Description: mappingRef.Description,
-			Source:      mappingRef.Url,
-			Props: &[]oscalTypes.Property{
-				{
-					Name:  extensions.FrameworkProp,
-					Value: mappingRef.Id,
-					Ns:    extensions.TrestleNameSpace,
-				},
-			},
+			Source:      sources[mappingRef.Id].Href,
+			Props:       &[]oscalTypes.Property{c.vocabulary.Prop(c.vocabulary.Framework, mappingRef.Id)},
 		}
 	}
 
-	var componentProps []oscalTypes.Property
+	componentProps := filter.props()
 	var groupNumber = 00
 
 	for _, family := range catalog.ControlFamilies {
+		if !filter.includesFamily(family) {
+			continue
+		}
 		for _, control := range family.Controls {
+			if !filter.includesControl(control) {
+				continue
+			}
 			for _, assessment := range control.AssessmentRequirements {
-				ruleProps := makeRule(assessment, groupNumber)
+				if !filter.includesRequirement(catalog, assessment) {
+					continue
+				}
+				ruleProps := makeRule(assessment, groupNumber, c.vocabulary)
 				groupNumber += 1
-				mapRule(assessment.Id, control.GuidelineMappings, mappingSet)
+				c.mapRule(control.Id, assessment, control.GuidelineMappings, mappingSet)
 				componentProps = append(componentProps, ruleProps...)
 			}
 		}
 	}
 
+	// Emit control implementation sets in mapping reference order
 	controlImplementations := make([]oscalTypes.ControlImplementationSet, 0, len(mappingSet))
-	for _, ciSet := range mappingSet {
+	for _, mappingRef := range catalog.Metadata.MappingReferences {
+		ciSet, ok := mappingSet[mappingRef.Id]
+		// Skip references without implemented requirements (e.g. all mapped rules are filtered out)
+		if !ok || len(ciSet.ImplementedRequirements) == 0 {
+			continue
+		}
+		sort.SliceStable(ciSet.ImplementedRequirements, func(i, j int) bool {
+			return ciSet.ImplementedRequirements[i].ControlId < ciSet.ImplementedRequirements[j].ControlId
+		})
+		for i := range ciSet.ImplementedRequirements {
+			setStatementsDescription(&ciSet.ImplementedRequirements[i])
+		}
+		c.diagnostics = append(c.diagnostics, sourceDiagnostics(mappingRef.Id, sources[mappingRef.Id], ciSet)...)
 		controlImplementations = append(controlImplementations, ciSet)
+		delete(mappingSet, mappingRef.Id)
 	}
 
 	component := oscalTypes.DefinedComponent{
-		UUID:                   uuid.NewUUID(),
+		UUID:                   c.uuids.Generate(targetComponent, catalog.Metadata.Id),
 		Title:                  targetComponent,
 		Type:                   componentType,
 		Props:                  utils.NilIfEmpty(&componentProps),
 		ControlImplementations: utils.NilIfEmpty(&controlImplementations),
 	}
+	if _, exists := c.targetComponents[catalog.Metadata.Id]; !exists {
+		c.targetOrder = append(c.targetOrder, catalog.Metadata.Id)
+	}
 	c.targetComponents[catalog.Metadata.Id] = component
+	c.catalogs[catalog.Metadata.Id] = catalog
 	return c
 }
 
+// CheckMappings returns a *normalize.CollisionError when distinct guideline mapping identifiers of
+// a mapping reference normalize to the same OSCAL control id, which would merge their rules into a
+// single implemented requirement. Collisions are also reported by Build.
+func (c *DefinitionBuilder) CheckMappings(catalog layer2.Catalog) error {
+	var errs []error
+	for _, collisionErr := range c.mappingCollisions(catalog) {
+		errs = append(errs, collisionErr)
+	}
+	return errors.Join(errs...)
+}
+
 func (c *DefinitionBuilder) AddValidationComponent(source string, evaluations []layer4.ControlEvaluation) *DefinitionBuilder {
 	var componentProps []oscalTypes.Property
 	var groupNumber = 00
@@ -87,7 +172,7 @@ func (c *DefinitionBuilder) AddValidationComponent(source string, evaluations []
 	for _, eval := range evaluations {
 		for _, assessment := range eval.Assessments {
 			for _, method := range assessment.Methods {
-				checkProps := makeCheck(assessment.Requirement_Id, method, groupNumber)
+				checkProps := makeCheck(assessment.Requirement_Id, method, groupNumber, c.vocabulary)
 				groupNumber += 1
 				componentProps = append(componentProps, checkProps...)
 			}
@@ -96,7 +181,7 @@ func (c *DefinitionBuilder) AddValidationComponent(source string, evaluations []
 	}
 
 	component := oscalTypes.DefinedComponent{
-		UUID:  uuid.NewUUID(),
+		UUID:  c.uuids.Generate("validation", source),
 		Type:  "validation",
 		Title: source,
 		Props: utils.NilIfEmpty(&componentProps),
@@ -107,69 +192,169 @@ func (c *DefinitionBuilder) AddValidationComponent(source string, evaluations []
 
 // AddParameterModifiers takes parameter modifications for a given Layer 2 reference and creates OSCAL set-parameters
 // on the associated control set implementations. This will only take effect is the Layer 2 Catalogs has been added
-// through AddTargetComponent.
-func (c *DefinitionBuilder) AddParameterModifiers(referenceId string, modifiers []layer3.ParameterModifier) *DefinitionBuilder {
+// through AddTargetComponent. ModifierOptions limit the set-parameters to specific mapping references and implemented
+// requirements.
+//
+// Modifiers are validated against the recommended parameters of the catalog. Modifiers with errors are not applied and
+// the issues are available from Diagnostics. A modifier for a parameter that is already set replaces the previous value
+// and excluded parameters are removed. A warning is reported for modifiers whose scope matches nothing.
+func (c *DefinitionBuilder) AddParameterModifiers(referenceId string, modifiers []layer3.ParameterModifier, opts ...ModifierOption) *DefinitionBuilder {
 	component, found := c.targetComponents[referenceId]
-	if found {
-		// Create set parameters
-		setParams := make([]oscalTypes.SetParameter, 0, len(modifiers))
-		for _, param := range modifiers {
-			setParameter := oscalTypes.SetParameter{
-				ParamId: param.TargetId,
-				Values:  []string{convertToString(param.Value)},
+	if !found {
+		c.diagnostics = append(c.diagnostics, controls.Diagnostic{
+			Severity: controls.SeverityError,
+			Location: referenceId,
+			Message:  "parameter modifiers reference a catalog that has not been added as a target component",
+		})
+		return c
+	}
+	var scope modifierScope
+	for _, opt := range opts {
+		opt(&scope)
+	}
+
+	for _, modifier := range modifiers {
+		diagnostics := validateModifier(c.catalogs[referenceId], modifier, c.orders[modifier.TargetId])
+		c.diagnostics = append(c.diagnostics, diagnostics...)
+		if slices.ContainsFunc(diagnostics, func(diagnostic controls.Diagnostic) bool {
+			return diagnostic.Severity == controls.SeverityError
+		}) {
+			continue
+		}
+
+		update := func(setParameters *[]oscalTypes.SetParameter) *[]oscalTypes.SetParameter {
+			if modifier.ModType == Exclude {
+				return removeParameter(setParameters, modifier.TargetId)
 			}
-			setParams = append(setParams, setParameter)
+			return setParameter(setParameters, oscalTypes.SetParameter{
+				ParamId: modifier.TargetId,
+				Values:  []string{utils.ConvertToString(modifier.Value)},
+			})
 		}
 
 		// Turn params modifiers into set parameters
+		var applied bool
+		var controlImplementations []oscalTypes.ControlImplementationSet
 		if component.ControlImplementations != nil {
-			for i := range *component.ControlImplementations {
-				ci := &(*component.ControlImplementations)[i]
-				if ci.SetParameters == nil {
-					ci.SetParameters = &setParams
-				} else {
-					*ci.SetParameters = append(*ci.SetParameters, setParams...)
+			controlImplementations = *component.ControlImplementations
+		}
+		for i := range controlImplementations {
+			ci := &controlImplementations[i]
+			if !scope.includesSet(*ci, c.vocabulary) {
+				continue
+			}
+			if len(scope.controlIds) == 0 {
+				ci.SetParameters = update(ci.SetParameters)
+				applied = true
+				continue
+			}
+			normalizer := c.normalizers.For(frameworkOf(*ci, c.vocabulary))
+			for j := range ci.ImplementedRequirements {
+				implRequirement := &ci.ImplementedRequirements[j]
+				if scope.includesRequirement(implRequirement.ControlId, normalizer) {
+					implRequirement.SetParameters = update(implRequirement.SetParameters)
+					applied = true
 				}
 			}
 		}
+		if !applied {
+			c.diagnostics = append(c.diagnostics, controls.Diagnostic{
+				Severity: controls.SeverityWarning,
+				Location: modifier.TargetId,
+				Message:  fmt.Sprintf("parameter modifier of %s matches no control implementation set or implemented requirement and was not applied", referenceId),
+			})
+		}
 	}
 	return c
 }
 
-func (c *DefinitionBuilder) Build() oscalTypes.ComponentDefinition {
+// AddPolicy applies the parameter modifications of a Layer 3 policy. Modifications of a control reference apply to
+// all control implementation sets of the target component for that catalog. Modifications of a guidance reference
+// apply to the control implementation sets for that mapping reference in every target component.
+func (c *DefinitionBuilder) AddPolicy(policy layer3.PolicyDocument) *DefinitionBuilder {
+	for _, reference := range policy.ControlReferences {
+		if len(reference.ParameterModifications) > 0 {
+			c.AddParameterModifiers(reference.ReferenceId, reference.ParameterModifications)
+		}
+	}
+	for _, reference := range policy.GuidanceReferences {
+		if len(reference.ParameterModifications) == 0 {
+			continue
+		}
+		var applied bool
+		for _, catalogId := range c.targetOrder {
+			if !slices.ContainsFunc(c.catalogs[catalogId].Metadata.MappingReferences, func(mappingRef layer2.MappingReference) bool {
+				return mappingRef.Id == reference.ReferenceId
+			}) {
+				continue
+			}
+			c.AddParameterModifiers(catalogId, reference.ParameterModifications, ForMappingReferences(reference.ReferenceId))
+			applied = true
+		}
+		if !applied {
+			c.diagnostics = append(c.diagnostics, controls.Diagnostic{
+				Severity: controls.SeverityWarning,
+				Location: reference.ReferenceId,
+				Message:  fmt.Sprintf("policy %s modifies a guidance reference that is not mapped by any target component", policy.Metadata.Id),
+			})
+		}
+	}
+	return c
+}
+
+// Diagnostics returns the issues found so far while adding components, parameter modifiers and policies.
+// Issues found across components are only reported by Build.
+func (c *DefinitionBuilder) Diagnostics() []controls.Diagnostic {
+	return c.diagnostics
+}
+
+// Build returns the component definition. A *BuildError holding every issue is returned when errors
+// were found or, in strict mode, when any issue was found. The component definition is returned
+// with the error so it can be inspected.
+func (c *DefinitionBuilder) Build() (oscalTypes.ComponentDefinition, error) {
 	metadata := models.NewSampleMetadata()
 	metadata.Title = c.title
 	metadata.Version = c.version
 
-	var allComponent []oscalTypes.DefinedComponent
-	for _, comp := range c.targetComponents {
-		allComponent = append(allComponent, comp)
+	var targets []oscalTypes.DefinedComponent
+	var sourceDates []time.Time
+	for _, id := range c.targetOrder {
+		targets = append(targets, c.targetComponents[id])
+		if lastModified, found := utils.ParseDate(c.catalogs[id].Metadata.LastModified, utils.DateLayouts); found {
+			sourceDates = append(sourceDates, lastModified)
+		}
 	}
-	allComponent = append(allComponent, c.validationComponent...)
+	metadata.LastModified = c.reproducibility.Now(sourceDates...)
+	allComponent := append(append([]oscalTypes.DefinedComponent{}, targets...), c.validationComponent...)
 
-	return oscalTypes.ComponentDefinition{
-		UUID:       uuid.NewUUID(),
+	componentDefinition := oscalTypes.ComponentDefinition{
+		UUID:       c.uuids.Generate(c.title, c.version),
 		Metadata:   metadata,
 		Components: utils.NilIfEmpty(&allComponent),
 	}
+	if c.existing != nil {
+		componentDefinition = mergeDefinition(*c.existing, componentDefinition, c.vocabulary)
+	}
+
+	diagnostics := append(append([]controls.Diagnostic{}, c.diagnostics...), componentDiagnostics(targets, c.validationComponent, c.vocabulary)...)
+	failed := c.strict && len(diagnostics) > 0
+	for _, diagnostic := range diagnostics {
+		failed = failed || diagnostic.Severity == controls.SeverityError
+	}
+	if failed {
+		return componentDefinition, &BuildError{Diagnostics: diagnostics}
+	}
+	return componentDefinition, nil
 }
 
-func makeRule(requirement layer2.AssessmentRequirement, groupNumber int) []oscalTypes.Property {
+func makeRule(requirement layer2.AssessmentRequirement, groupNumber int, vocab vocabulary.Vocabulary) []oscalTypes.Property {
 	remark := fmt.Sprintf("rule_set_%d", groupNumber)
 
-	ruleIdProp := oscalTypes.Property{
-		Name:    extensions.RuleIdProp,
-		Value:   requirement.Id,
-		Ns:      extensions.TrestleNameSpace,
-		Remarks: remark,
-	}
+	ruleIdProp := vocab.Prop(vocab.RuleId, requirement.Id)
+	ruleIdProp.Remarks = remark
 
-	ruleDescProp := oscalTypes.Property{
-		Name:    extensions.RuleDescriptionProp,
-		Value:   strings.ReplaceAll(requirement.Text, "\n", "\\n"),
-		Ns:      extensions.TrestleNameSpace,
-		Remarks: remark,
-	}
+	ruleDescProp := vocab.Prop(vocab.RuleDescription, strings.ReplaceAll(requirement.Text, "\n", "\\n"))
+	ruleDescProp.Remarks = remark
 
 	props := []oscalTypes.Property{
 		ruleIdProp,
@@ -178,27 +363,15 @@ func makeRule(requirement layer2.AssessmentRequirement, groupNumber int) []oscal
 
 	if len(requirement.RecommendedParameters) > 0 {
 		for i, parameter := range requirement.RecommendedParameters {
-			paramIdProp := oscalTypes.Property{
-				Name:    fmt.Sprintf("%s_%d", extensions.ParameterIdProp, i),
-				Value:   parameter.Id,
-				Ns:      extensions.TrestleNameSpace,
-				Remarks: remark,
-			}
+			paramIdProp := vocab.Prop(vocab.Indexed(vocab.ParameterId, i), parameter.Id)
+			paramIdProp.Remarks = remark
 
-			paramDescProp := oscalTypes.Property{
-				Name:    fmt.Sprintf("%s_%d", extensions.ParameterDescriptionProp, i),
-				Value:   strings.ReplaceAll(parameter.Description, "\n", "\\n"),
-				Ns:      extensions.TrestleNameSpace,
-				Remarks: remark,
-			}
+			paramDescProp := vocab.Prop(vocab.Indexed(vocab.ParameterDescription, i), strings.ReplaceAll(parameter.Description, "\n", "\\n"))
+			paramDescProp.Remarks = remark
 
 			if parameter.Default != nil {
-				parameterDefaultProp := oscalTypes.Property{
-					Name:    fmt.Sprintf("%s_%d", extensions.ParameterDefaultProp, i),
-					Value:   convertToString(parameter.Default),
-					Ns:      extensions.TrestleNameSpace,
-					Remarks: remark,
-				}
+				parameterDefaultProp := vocab.Prop(vocab.Indexed(vocab.ParameterDefault, i), utils.ConvertToString(parameter.Default))
+				parameterDefaultProp.Remarks = remark
 				props = append(props, parameterDefaultProp)
 			}
 
@@ -209,28 +382,16 @@ func makeRule(requirement layer2.AssessmentRequirement, groupNumber int) []oscal
 	return props
 }
 
-func makeCheck(ruleId string, method layer4.AssessmentMethod, groupNumber int) []oscalTypes.Property {
+func makeCheck(ruleId string, method layer4.AssessmentMethod, groupNumber int, vocab vocabulary.Vocabulary) []oscalTypes.Property {
 	remark := fmt.Sprintf("rule_set_%d", groupNumber)
-	ruleIdProp := oscalTypes.Property{
-		Name:    extensions.RuleIdProp,
-		Value:   ruleId,
-		Ns:      extensions.TrestleNameSpace,
-		Remarks: remark,
-	}
+	ruleIdProp := vocab.Prop(vocab.RuleId, ruleId)
+	ruleIdProp.Remarks = remark
 
-	checkIdProp := oscalTypes.Property{
-		Name:    extensions.CheckIdProp,
-		Value:   method.Name,
-		Ns:      extensions.TrestleNameSpace,
-		Remarks: remark,
-	}
+	checkIdProp := vocab.Prop(vocab.CheckId, method.Name)
+	checkIdProp.Remarks = remark
 
-	checkDescProp := oscalTypes.Property{
-		Name:    extensions.CheckDescriptionProp,
-		Value:   method.Description,
-		Ns:      extensions.TrestleNameSpace,
-		Remarks: remark,
-	}
+	checkDescProp := vocab.Prop(vocab.CheckDescription, method.Description)
+	checkDescProp.Remarks = remark
 	return []oscalTypes.Property{
 		ruleIdProp,
 		checkIdProp,
@@ -238,61 +399,143 @@ func makeCheck(ruleId string, method layer4.AssessmentMethod, groupNumber int) [
 	}
 }
 
-func mapRule(ruleId string, mappings []layer2.Mapping, ciSets map[string]oscalTypes.ControlImplementationSet) {
-	ruleIdProp := oscalTypes.Property{
-		Name:  extensions.RuleIdProp,
-		Value: ruleId,
-		Ns:    extensions.TrestleNameSpace,
+// statementRe matches mapping identifiers that target a lettered control statement part,
+// e.g. AC-2a, AC-2.a, AC-2(a) or AC-2(1)(a).
+var statementRe = regexp.MustCompile(`^(.*\d\)?)\.?(?:\(([a-z])\)|([a-z]))$`)
+
+// splitStatement splits a mapping identifier into the control identifier and the statement part
+// it targets, if any.
+func splitStatement(identifier string) (string, string) {
+	matches := statementRe.FindStringSubmatch(identifier)
+	if matches == nil {
+		return identifier, ""
 	}
+	return matches[1], matches[2] + matches[3]
+}
 
+// mapRule adds the rule for an assessment requirement to the implemented requirements for the guideline mappings of
+// the control. Mappings weaker than the minimum strength are skipped.
+func (c *DefinitionBuilder) mapRule(controlId string, requirement layer2.AssessmentRequirement, mappings []layer2.Mapping, ciSets map[string]oscalTypes.ControlImplementationSet) {
 	for _, mapping := range mappings {
 		targetCI, ok := ciSets[mapping.ReferenceId]
 		if !ok {
 			continue
 		}
+		normalizer := c.normalizers.For(mapping.ReferenceId)
 		for _, identifier := range mapping.Identifiers {
-			createOrUpdateImplementedRequirement(ruleIdProp, identifier, &targetCI)
+			ruleProps := []oscalTypes.Property{c.vocabulary.Prop(c.vocabulary.RuleId, requirement.Id)}
+			var mappingRemarks string
+			if c.mappingMetadata != nil {
+				if metadata, found := c.mappingMetadata(controlId, mapping.ReferenceId, identifier); found {
+					if metadata.Strength > 0 && metadata.Strength < c.minimumStrength {
+						continue
+					}
+					ruleProps = append(ruleProps, metadata.props(requirement.Id, c.vocabulary)...)
+					mappingRemarks = metadata.remarks(requirement.Id)
+				}
+			}
+			controlIdentifier, statement := splitStatement(identifier)
+			createOrUpdateImplementedRequirement(requirement, ruleProps, mappingRemarks, normalizer.Normalize(controlIdentifier), statement, &targetCI, c.uuids)
 		}
 		ciSets[mapping.ReferenceId] = targetCI
 	}
 }
 
-func createOrUpdateImplementedRequirement(ruleIdProp oscalTypes.Property, identifier string, controlImplementation *oscalTypes.ControlImplementationSet) {
-	var found bool
-	for i := range controlImplementation.ImplementedRequirements {
-		if controlImplementation.ImplementedRequirements[i].ControlId == identifier {
-			if controlImplementation.ImplementedRequirements[i].Props == nil {
-				controlImplementation.ImplementedRequirements[i].Props = &[]oscalTypes.Property{}
-			}
-			*controlImplementation.ImplementedRequirements[i].Props = append(*controlImplementation.ImplementedRequirements[i].Props, ruleIdProp)
-			found = true
-			break
-		}
+// createOrUpdateImplementedRequirement adds the rule for an assessment requirement to the implemented requirement for the
+// control, or to its statement when the mapping targets a statement part. The assessment requirement text and recommendation
+// are aggregated into the description and remarks of the implemented requirement or statement with the mapping remarks, and
+// the rule props (the rule id and mapping metadata) are added.
+func createOrUpdateImplementedRequirement(requirement layer2.AssessmentRequirement, ruleProps []oscalTypes.Property, mappingRemarks, controlId, statement string, controlImplementation *oscalTypes.ControlImplementationSet, uuids utils.UUIDGenerator) {
+	description := requirementParagraph(requirement.Id, requirement.Text)
+	remarks := requirementParagraph(requirement.Id, requirement.Recommendation)
+
+	index := slices.IndexFunc(controlImplementation.ImplementedRequirements, func(implRequirement oscalTypes.ImplementedRequirementControlImplementation) bool {
+		return implRequirement.ControlId == controlId
+	})
+	// Check if it is set, this means create a new one
+	if index < 0 {
+		controlImplementation.ImplementedRequirements = append(controlImplementation.ImplementedRequirements, oscalTypes.ImplementedRequirementControlImplementation{
+			UUID:      uuids.Generate(controlImplementation.UUID, controlId),
+			ControlId: controlId,
+		})
+		index = len(controlImplementation.ImplementedRequirements) - 1
 	}
+	implRequirement := &controlImplementation.ImplementedRequirements[index]
 
-	// Check if it is set, this means create a new one
-	if !found {
-		implRequirement := oscalTypes.ImplementedRequirementControlImplementation{
-			UUID:      uuid.NewUUID(),
-			ControlId: utils.NormalizeControl(identifier),
-			Props:     &[]oscalTypes.Property{ruleIdProp},
+	if statement == "" {
+		implRequirement.Description = appendParagraph(implRequirement.Description, description)
+		implRequirement.Remarks = appendParagraph(appendParagraph(implRequirement.Remarks, remarks), mappingRemarks)
+		for _, prop := range ruleProps {
+			implRequirement.Props = appendProp(implRequirement.Props, prop)
 		}
-		controlImplementation.ImplementedRequirements = append(controlImplementation.ImplementedRequirements, implRequirement)
+		return
+	}
+
+	statementId := fmt.Sprintf("%s_smt.%s", controlId, statement)
+	if implRequirement.Statements == nil {
+		implRequirement.Statements = &[]oscalTypes.ControlStatementImplementation{}
+	}
+	statements := implRequirement.Statements
+	statementIndex := slices.IndexFunc(*statements, func(existing oscalTypes.ControlStatementImplementation) bool {
+		return existing.StatementId == statementId
+	})
+	if statementIndex < 0 {
+		*statements = append(*statements, oscalTypes.ControlStatementImplementation{
+			UUID:        uuids.Generate(implRequirement.UUID, statementId),
+			StatementId: statementId,
+		})
+		statementIndex = len(*statements) - 1
+	}
+	implStatement := &(*statements)[statementIndex]
+	for _, prop := range ruleProps {
+		implStatement.Props = appendProp(implStatement.Props, prop)
 	}
+	implStatement.Description = appendParagraph(implStatement.Description, description)
+	implStatement.Remarks = appendParagraph(appendParagraph(implStatement.Remarks, remarks), mappingRemarks)
 }
 
-func convertToString(val any) string {
-	if val == nil {
+// setStatementsDescription sets the description of an implemented requirement that only has
+// statement mappings, since the description is required and statement text is not repeated on
+// the implemented requirement.
+func setStatementsDescription(implRequirement *oscalTypes.ImplementedRequirementControlImplementation) {
+	if implRequirement.Description != "" || implRequirement.Statements == nil {
+		return
+	}
+	statementIds := make([]string, 0, len(*implRequirement.Statements))
+	for _, statement := range *implRequirement.Statements {
+		statementIds = append(statementIds, statement.StatementId)
+	}
+	implRequirement.Description = fmt.Sprintf("Implemented by statements %s.", strings.Join(statementIds, ", "))
+}
+
+// requirementParagraph returns the assessment requirement text prefixed with its id.
+func requirementParagraph(requirementId, text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
 		return ""
 	}
-	switch v := val.(type) {
-	case string:
-		return v
-	case int:
-		return strconv.Itoa(v)
-	case int32:
-		return strconv.FormatInt(int64(v), 10)
+	return fmt.Sprintf("%s: %s", requirementId, text)
+}
+
+// appendParagraph appends a paragraph to text unless it is empty or already present.
+func appendParagraph(text, paragraph string) string {
+	switch {
+	case paragraph == "" || slices.Contains(strings.Split(text, "\n\n"), paragraph):
+		return text
+	case text == "":
+		return paragraph
 	default:
-		return fmt.Sprint(v)
+		return text + "\n\n" + paragraph
 	}
 }
+
+// appendProp appends a prop unless it is already present.
+func appendProp(props *[]oscalTypes.Property, prop oscalTypes.Property) *[]oscalTypes.Property {
+	if props == nil {
+		return &[]oscalTypes.Property{prop}
+	}
+	if !slices.Contains(*props, prop) {
+		*props = append(*props, prop)
+	}
+	return props
+}
@@ -2,7 +2,9 @@ package component
 
 import (
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/goccy/go-yaml"
@@ -12,6 +14,10 @@ import (
 	"github.com/ossf/gemara/layer3"
 	"github.com/ossf/gemara/layer4"
 	"github.com/stretchr/testify/require"
+
+	"github.com/jpower432/gemara2oscal/controls"
+	"github.com/jpower432/gemara2oscal/normalize"
+	"github.com/jpower432/gemara2oscal/vocabulary"
 )
 
 func TestDefinitionBuilder_Build(t *testing.T) {
@@ -39,7 +45,8 @@ func TestDefinitionBuilder_Build(t *testing.T) {
 	}
 
 	builder := NewDefinitionBuilder("ComponentDefinition", "v0.1.0")
-	componentDefinition := builder.AddTargetComponent("Example", "software", catalog).AddValidationComponent("myvalidator", []layer4.ControlEvaluation{eval}).Build()
+	componentDefinition, err := builder.AddTargetComponent("Example", "software", catalog).AddValidationComponent("myvalidator", []layer4.ControlEvaluation{eval}).Build()
+	require.NoError(t, err)
 	require.Len(t, *componentDefinition.Components, 2)
 
 	components := *componentDefinition.Components
@@ -58,13 +65,273 @@ func TestDefinitionBuilder_Build(t *testing.T) {
 	err = validator.Validate(oscalModels)
 	require.NoError(t, err)
 
-	componentDefinition = builder.AddParameterModifiers("OSPS-B", []layer3.ParameterModifier{{
+	componentDefinition, err = builder.AddParameterModifiers("OSPS-B", []layer3.ParameterModifier{{
 		TargetId: "main_branch_min_approvals",
 		ModType:  "tighten",
 		Value:    2,
 	}}).Build()
+	require.NoError(t, err)
 	require.Len(t, *componentDefinition.Components, 2)
 	ci = *components[0].ControlImplementations
 	require.Len(t, ci, 1)
 	require.Equal(t, []oscalTypes.SetParameter{{ParamId: "main_branch_min_approvals", Values: []string{"2"}}}, *ci[0].SetParameters)
 }
+
+func TestDefinitionBuilder_Deterministic(t *testing.T) {
+	file, err := os.Open("./testdata/good-osps.yml")
+	require.NoError(t, err)
+
+	var catalog layer2.Catalog
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&catalog)
+	require.NoError(t, err)
+
+	build := func(opts ...Option) oscalTypes.ComponentDefinition {
+		componentDefinition, err := NewDefinitionBuilder("ComponentDefinition", "v0.1.0", append([]Option{WithDeterministicUUIDs()}, opts...)...).
+			AddTargetComponent("Example", "software", catalog).
+			AddValidationComponent("myvalidator", nil).
+			Build()
+		require.NoError(t, err)
+		return componentDefinition
+	}
+
+	first := build()
+	second := build()
+	require.Equal(t, first, second)
+	// The catalog has no last modified date
+	require.Equal(t, time.Unix(0, 0).UTC(), first.Metadata.LastModified)
+
+	timestamp := time.Date(2025, 7, 26, 15, 0, 0, 0, time.UTC)
+	require.Equal(t, timestamp, build(WithTimestamp(timestamp)).Metadata.LastModified)
+	catalog.Metadata.LastModified = "2025-07-26 15:00:00"
+	require.Equal(t, timestamp, build().Metadata.LastModified)
+
+	ci := (*first.Components)[0].ControlImplementations
+	var controlIds []string
+	for _, implReq := range (*ci)[0].ImplementedRequirements {
+		controlIds = append(controlIds, implReq.ControlId)
+	}
+	require.Equal(t, []string{"ac-5", "au-6", "pl-8", "sa-15", "sr-3"}, controlIds)
+}
+
+func TestDefinitionBuilder_ReferenceNormalizer(t *testing.T) {
+	file, err := os.Open("./testdata/good-osps.yml")
+	require.NoError(t, err)
+
+	var catalog layer2.Catalog
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&catalog)
+	require.NoError(t, err)
+	catalog.Metadata.MappingReferences = append(catalog.Metadata.MappingReferences, layer2.MappingReference{
+		Id:    "PCIDSS",
+		Title: "Payment Card Industry Data Security Standard",
+	})
+
+	builder := NewDefinitionBuilder("ComponentDefinition", "v0.1.0", WithReferenceNormalizer("PCIDSS", normalize.PCI))
+	require.NoError(t, builder.CheckMappings(catalog))
+
+	componentDefinition, err := builder.AddTargetComponent("Example", "software", catalog).Build()
+	require.NoError(t, err)
+	ci := (*componentDefinition.Components)[0].ControlImplementations
+	require.Len(t, *ci, 2)
+	require.Equal(t, "pci-6.2.3.1", (*ci)[1].ImplementedRequirements[0].ControlId)
+
+	// Keeping only the PCI DSS principal requirement merges distinct requirements
+	truncate := normalize.RegexTable{Rules: []normalize.Rule{normalize.NewRule(`^(\d+)\..*$`, "pci-$1")}}
+	builder = NewDefinitionBuilder("ComponentDefinition", "v0.1.0", WithReferenceNormalizer("PCIDSS", truncate))
+	err = builder.CheckMappings(catalog)
+	var collisionErr *normalize.CollisionError
+	require.ErrorAs(t, err, &collisionErr)
+	require.Equal(t, "PCIDSS", collisionErr.Scope)
+
+	_, err = builder.AddTargetComponent("Example", "software", catalog).Build()
+	var buildErr *BuildError
+	require.ErrorAs(t, err, &buildErr)
+	require.Equal(t, controls.SeverityError, buildErr.Diagnostics[0].Severity)
+	require.Equal(t, "pci-6", buildErr.Diagnostics[0].Location)
+}
+
+func TestDefinitionBuilder_TargetFilter(t *testing.T) {
+	file, err := os.Open("./testdata/good-osps.yml")
+	require.NoError(t, err)
+
+	var catalog layer2.Catalog
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&catalog)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		opts      []TargetOption
+		wantProps []oscalTypes.Property
+		wantRules bool
+	}{
+		{
+			name: "Applicable by id",
+			opts: []TargetOption{WithApplicability("Maturity3")},
+			wantProps: []oscalTypes.Property{
+				{Name: ApplicabilityProp, Value: "Maturity3", Ns: vocabulary.GemaraNameSpace},
+			},
+			wantRules: true,
+		},
+		{
+			name: "Applicable by title",
+			opts: []TargetOption{WithApplicability("Maturity Level 3"), WithFamilies("quality")},
+			wantProps: []oscalTypes.Property{
+				{Name: ApplicabilityProp, Value: "Maturity3", Ns: vocabulary.GemaraNameSpace},
+				{Name: IncludeFamilyProp, Value: "quality", Ns: vocabulary.GemaraNameSpace},
+			},
+			wantRules: true,
+		},
+		{
+			name: "Not applicable",
+			opts: []TargetOption{WithApplicability("Maturity1")},
+			wantProps: []oscalTypes.Property{
+				{Name: ApplicabilityProp, Value: "Maturity1", Ns: vocabulary.GemaraNameSpace},
+			},
+		},
+		{
+			name: "Excluded control",
+			opts: []TargetOption{WithoutControls("OSPS-QA-07")},
+			wantProps: []oscalTypes.Property{
+				{Name: ExcludeControlProp, Value: "OSPS-QA-07", Ns: vocabulary.GemaraNameSpace},
+			},
+		},
+		{
+			name: "Excluded family",
+			opts: []TargetOption{WithoutFamilies("Quality")},
+			wantProps: []oscalTypes.Property{
+				{Name: ExcludeFamilyProp, Value: "Quality", Ns: vocabulary.GemaraNameSpace},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			componentDefinition, err := NewDefinitionBuilder("ComponentDefinition", "v0.1.0").
+				AddTargetComponent("Example", "software", catalog, tt.opts...).
+				Build()
+			require.NoError(t, err)
+			component := (*componentDefinition.Components)[0]
+			props := *component.Props
+			require.Equal(t, tt.wantProps, props[:len(tt.wantProps)])
+
+			if !tt.wantRules {
+				require.Len(t, props, len(tt.wantProps))
+				require.Nil(t, component.ControlImplementations)
+				return
+			}
+			require.Len(t, props, len(tt.wantProps)+5)
+			require.Len(t, *component.ControlImplementations, 1)
+
+			err = validation.NewSchemaValidator().Validate(oscalTypes.OscalModels{ComponentDefinition: &componentDefinition})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDefinitionBuilder_ImplementedRequirementContent(t *testing.T) {
+	file, err := os.Open("./testdata/good-osps.yml")
+	require.NoError(t, err)
+
+	var catalog layer2.Catalog
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&catalog)
+	require.NoError(t, err)
+
+	control := &catalog.ControlFamilies[0].Controls[0]
+	control.GuidelineMappings = []layer2.Mapping{{ReferenceId: "800-161", Identifiers: []string{"AC-5", "AU-6(a)", "SA-15(1)b"}}}
+	requirement := control.AssessmentRequirements[0]
+	description := "OSPS-QA-07.01: " + strings.TrimSpace(requirement.Text)
+	remarks := "OSPS-QA-07.01: " + strings.TrimSpace(requirement.Recommendation)
+	ruleIdProp := oscalTypes.Property{Name: extensions.RuleIdProp, Value: "OSPS-QA-07.01", Ns: extensions.TrestleNameSpace}
+
+	componentDefinition, err := NewDefinitionBuilder("ComponentDefinition", "v0.1.0").
+		AddTargetComponent("Example", "software", catalog).
+		Build()
+	require.NoError(t, err)
+
+	implRequirements := (*(*componentDefinition.Components)[0].ControlImplementations)[0].ImplementedRequirements
+	require.Len(t, implRequirements, 3)
+
+	ac5 := implRequirements[0]
+	require.Equal(t, "ac-5", ac5.ControlId)
+	require.Equal(t, description, ac5.Description)
+	require.Equal(t, remarks, ac5.Remarks)
+	require.Equal(t, []oscalTypes.Property{ruleIdProp}, *ac5.Props)
+	require.Nil(t, ac5.Statements)
+
+	au6 := implRequirements[1]
+	require.Equal(t, "au-6", au6.ControlId)
+	// Statement text is only on the statement
+	require.Equal(t, "Implemented by statements au-6_smt.a.", au6.Description)
+	require.Empty(t, au6.Remarks)
+	require.Nil(t, au6.Props)
+	statements := *au6.Statements
+	require.Len(t, statements, 1)
+	require.Equal(t, "au-6_smt.a", statements[0].StatementId)
+	require.Equal(t, description, statements[0].Description)
+	require.Equal(t, remarks, statements[0].Remarks)
+	require.Equal(t, []oscalTypes.Property{ruleIdProp}, *statements[0].Props)
+
+	sa15 := implRequirements[2]
+	require.Equal(t, "sa-15.1", sa15.ControlId)
+	require.Equal(t, "sa-15.1_smt.b", (*sa15.Statements)[0].StatementId)
+
+	err = validation.NewSchemaValidator().Validate(oscalTypes.OscalModels{ComponentDefinition: &componentDefinition})
+	require.NoError(t, err)
+}
+
+func TestDefinitionBuilder_Vocabulary(t *testing.T) {
+	file, err := os.Open("./testdata/good-osps.yml")
+	require.NoError(t, err)
+
+	var catalog layer2.Catalog
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&catalog)
+	require.NoError(t, err)
+
+	eval := layer4.ControlEvaluation{
+		Control_Id: "OSPS-QA-07",
+		Assessments: []*layer4.Assessment{
+			{
+				Requirement_Id: "OSPS-QA-07.01",
+				Methods:        []layer4.AssessmentMethod{{Name: "my-check-id", Description: "My method"}},
+			},
+		},
+	}
+
+	componentDefinition, err := NewDefinitionBuilder("ComponentDefinition", "v0.1.0", WithVocabulary(vocabulary.Gemara)).
+		AddTargetComponent("Example", "software", catalog).
+		AddValidationComponent("myvalidator", []layer4.ControlEvaluation{eval}).
+		AddParameterModifiers("OSPS-B", []layer3.ParameterModifier{{TargetId: "main_branch_min_approvals", ModType: "tighten", Value: 2}}).
+		Build()
+	require.NoError(t, err)
+
+	components := *componentDefinition.Components
+	require.Equal(t, oscalTypes.Property{
+		Name:    "assessment-requirement-id",
+		Value:   "OSPS-QA-07.01",
+		Ns:      vocabulary.GemaraNameSpace,
+		Remarks: "rule_set_0",
+	}, (*components[0].Props)[0])
+	require.Equal(t, oscalTypes.Property{
+		Name:    "assessment-method",
+		Value:   "my-check-id",
+		Ns:      vocabulary.GemaraNameSpace,
+		Remarks: "rule_set_0",
+	}, (*components[1].Props)[1])
+
+	ci := (*components[0].ControlImplementations)[0]
+	require.Equal(t, []oscalTypes.Property{{Name: "mapping-reference", Value: "800-161", Ns: vocabulary.GemaraNameSpace}}, *ci.Props)
+	require.Equal(t, []oscalTypes.SetParameter{{ParamId: "main_branch_min_approvals", Values: []string{"2"}}}, *ci.SetParameters)
+	for _, implRequirement := range ci.ImplementedRequirements {
+		if implRequirement.Props != nil {
+			for _, prop := range *implRequirement.Props {
+				require.Equal(t, vocabulary.GemaraNameSpace, prop.Ns)
+			}
+		}
+	}
+
+	err = validation.NewSchemaValidator().Validate(oscalTypes.OscalModels{ComponentDefinition: &componentDefinition})
+	require.NoError(t, err)
+}
@@ -0,0 +1,154 @@
+package component
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/ossf/gemara/layer2"
+
+	"github.com/jpower432/gemara2oscal/controls"
+	"github.com/jpower432/gemara2oscal/vocabulary"
+)
+
+// Component props recording the filter applied to a target component. They describe Gemara
+// selections, so they are always emitted in the Gemara namespace (vocabulary.GemaraNameSpace).
+const (
+	ApplicabilityProp  = "applicability-category"
+	IncludeFamilyProp  = "include-family"
+	ExcludeFamilyProp  = "exclude-family"
+	IncludeControlProp = "include-control"
+	ExcludeControlProp = "exclude-control"
+)
+
+type filter struct {
+	applicability   []string
+	includeFamilies []string
+	excludeFamilies []string
+	includeControls []string
+	excludeControls []string
+}
+
+// TargetOption selects the assessment requirements of a Layer 2 catalog that apply to a
+// target component.
+type TargetOption func(f *filter)
+
+// WithApplicability only includes assessment requirements that apply to at least one of
+// the given applicability categories. Categories are matched by id or title.
+func WithApplicability(categories ...string) TargetOption {
+	return func(f *filter) {
+		f.applicability = append(f.applicability, categories...)
+	}
+}
+
+// WithFamilies only includes controls of the given control families, matched by title.
+func WithFamilies(titles ...string) TargetOption {
+	return func(f *filter) {
+		f.includeFamilies = append(f.includeFamilies, titles...)
+	}
+}
+
+// WithoutFamilies excludes controls of the given control families, matched by title.
+func WithoutFamilies(titles ...string) TargetOption {
+	return func(f *filter) {
+		f.excludeFamilies = append(f.excludeFamilies, titles...)
+	}
+}
+
+// WithControls only includes the controls with the given Layer 2 ids.
+func WithControls(controlIds ...string) TargetOption {
+	return func(f *filter) {
+		f.includeControls = append(f.includeControls, controlIds...)
+	}
+}
+
+// WithoutControls excludes the controls with the given Layer 2 ids.
+func WithoutControls(controlIds ...string) TargetOption {
+	return func(f *filter) {
+		f.excludeControls = append(f.excludeControls, controlIds...)
+	}
+}
+
+// newFilter returns the filter for the target options with an error diagnostic for every
+// applicability category that is not defined in the catalog, since it filters out every rule.
+func newFilter(targetComponent string, catalog layer2.Catalog, opts []TargetOption) (filter, []controls.Diagnostic) {
+	var f filter
+	for _, opt := range opts {
+		opt(&f)
+	}
+	// Record categories by id so requirements referencing either the id or the title match
+	var diagnostics []controls.Diagnostic
+	for i, category := range f.applicability {
+		id, found := categoryId(catalog, category)
+		if !found {
+			diagnostics = append(diagnostics, controls.Diagnostic{
+				Severity: controls.SeverityError,
+				Location: targetComponent,
+				Message:  fmt.Sprintf("applicability category %q is not defined in %s", category, catalog.Metadata.Id),
+			})
+		}
+		f.applicability[i] = id
+	}
+	return f, diagnostics
+}
+
+// categoryId returns the id of the applicability category with the given id or title.
+// Unknown categories are returned unchanged and reported as not found.
+func categoryId(catalog layer2.Catalog, reference string) (string, bool) {
+	reference = strings.TrimSpace(reference)
+	for _, category := range catalog.Metadata.ApplicabilityCategories {
+		if category.Id == reference || category.Title == reference {
+			return category.Id, true
+		}
+	}
+	return reference, false
+}
+
+func (f filter) includesFamily(family layer2.ControlFamily) bool {
+	if len(f.includeFamilies) > 0 && !containsFold(f.includeFamilies, family.Title) {
+		return false
+	}
+	return !containsFold(f.excludeFamilies, family.Title)
+}
+
+func (f filter) includesControl(control layer2.Control) bool {
+	if len(f.includeControls) > 0 && !containsFold(f.includeControls, control.Id) {
+		return false
+	}
+	return !containsFold(f.excludeControls, control.Id)
+}
+
+func (f filter) includesRequirement(catalog layer2.Catalog, requirement layer2.AssessmentRequirement) bool {
+	if len(f.applicability) == 0 {
+		return true
+	}
+	for _, reference := range requirement.Applicability {
+		if id, _ := categoryId(catalog, reference); slices.Contains(f.applicability, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// props returns the component props recording the filter.
+func (f filter) props() []oscalTypes.Property {
+	var props []oscalTypes.Property
+	addProps := func(name string, values []string) {
+		for _, value := range values {
+			props = append(props, vocabulary.Gemara.Prop(name, value))
+		}
+	}
+	addProps(ApplicabilityProp, f.applicability)
+	addProps(IncludeFamilyProp, f.includeFamilies)
+	addProps(ExcludeFamilyProp, f.excludeFamilies)
+	addProps(IncludeControlProp, f.includeControls)
+	addProps(ExcludeControlProp, f.excludeControls)
+	return props
+}
+
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(candidate string) bool {
+		return strings.EqualFold(strings.TrimSpace(candidate), value)
+	})
+}
@@ -0,0 +1,211 @@
+package component
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/ossf/gemara/layer2"
+	"github.com/ossf/gemara/layer3"
+
+	"github.com/jpower432/gemara2oscal/controls"
+	"github.com/jpower432/gemara2oscal/internal/utils"
+	"github.com/jpower432/gemara2oscal/normalize"
+	"github.com/jpower432/gemara2oscal/vocabulary"
+)
+
+// Layer 3 parameter modification types. Tighten and Loosen are accepted as aliases.
+const (
+	IncreaseStrictness layer3.ModType = "increase-strictness"
+	Clarify            layer3.ModType = "clarify"
+	ReduceStrictness   layer3.ModType = "reduce-strictness"
+	Exclude            layer3.ModType = "exclude"
+	Tighten            layer3.ModType = "tighten"
+	Loosen             layer3.ModType = "loosen"
+)
+
+// ParameterOrder describes how the values of a recommended parameter compare in strictness.
+// Numeric values are compared as numbers, higher values being stricter by default.
+type ParameterOrder struct {
+	// LowerIsStricter reverses the numeric comparison (e.g. for a maximum age).
+	LowerIsStricter bool
+	// Values lists non-numeric values from least to most strict.
+	Values []string
+}
+
+// compare returns -1, 0 or 1 when value is less strict, as strict or stricter than
+// the default. The second return value is false when the values cannot be compared.
+func (p ParameterOrder) compare(value, defaultValue string) (int, bool) {
+	if len(p.Values) > 0 {
+		valueIndex, defaultIndex := slices.Index(p.Values, value), slices.Index(p.Values, defaultValue)
+		if valueIndex < 0 || defaultIndex < 0 {
+			return 0, false
+		}
+		return sign(float64(valueIndex - defaultIndex)), true
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	defaultNumber, err := strconv.ParseFloat(defaultValue, 64)
+	if err != nil {
+		return 0, false
+	}
+	if p.LowerIsStricter {
+		return sign(defaultNumber - number), true
+	}
+	return sign(number - defaultNumber), true
+}
+
+func sign(value float64) int {
+	switch {
+	case value > 0:
+		return 1
+	case value < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// validateModifier checks a parameter modifier against the recommended parameter of the catalog.
+// Modifiers with error diagnostics must not be applied.
+func validateModifier(catalog layer2.Catalog, modifier layer3.ParameterModifier, order ParameterOrder) []controls.Diagnostic {
+	var diagnostics []controls.Diagnostic
+	report := func(severity controls.Severity, format string, args ...any) {
+		diagnostics = append(diagnostics, controls.Diagnostic{
+			Severity: severity,
+			Location: modifier.TargetId,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	parameter, found := findParameter(catalog, modifier.TargetId)
+	if !found {
+		report(controls.SeverityError, "parameter is not a recommended parameter of %s", catalog.Metadata.Id)
+		return diagnostics
+	}
+
+	value := utils.ConvertToString(modifier.Value)
+	comparison, comparable := 0, false
+	if parameter.Default != nil {
+		comparison, comparable = order.compare(value, utils.ConvertToString(parameter.Default))
+	}
+
+	switch modifier.ModType {
+	case IncreaseStrictness, Tighten:
+		if comparable && comparison < 0 {
+			report(controls.SeverityError, "%s value %s is less strict than the default %s", modifier.ModType, value, utils.ConvertToString(parameter.Default))
+		} else if comparable && comparison == 0 {
+			report(controls.SeverityWarning, "%s value %s does not change the default", modifier.ModType, value)
+		}
+	case ReduceStrictness, Loosen:
+		if strings.TrimSpace(modifier.ModificationRationale) == "" {
+			report(controls.SeverityError, "%s requires a modification rationale", modifier.ModType)
+		}
+		if comparable && comparison > 0 {
+			report(controls.SeverityError, "%s value %s is stricter than the default %s", modifier.ModType, value, utils.ConvertToString(parameter.Default))
+		}
+	case Exclude:
+		if strings.TrimSpace(modifier.ModificationRationale) == "" {
+			report(controls.SeverityError, "%s requires a modification rationale", modifier.ModType)
+		}
+	case Clarify:
+		if comparable && comparison != 0 {
+			report(controls.SeverityWarning, "%s changes the default value %s to %s", modifier.ModType, utils.ConvertToString(parameter.Default), value)
+		}
+	default:
+		report(controls.SeverityError, "unknown modification type %q", modifier.ModType)
+	}
+	return diagnostics
+}
+
+// findParameter returns the recommended parameter with the given id.
+func findParameter(catalog layer2.Catalog, parameterId string) (layer2.Parameter, bool) {
+	for _, family := range catalog.ControlFamilies {
+		for _, control := range family.Controls {
+			for _, requirement := range control.AssessmentRequirements {
+				for _, parameter := range requirement.RecommendedParameters {
+					if parameter.Id == parameterId {
+						return parameter, true
+					}
+				}
+			}
+		}
+	}
+	return layer2.Parameter{}, false
+}
+
+// setParameter replaces the set-parameter with the same param id or appends a new one.
+func setParameter(setParameters *[]oscalTypes.SetParameter, parameter oscalTypes.SetParameter) *[]oscalTypes.SetParameter {
+	if setParameters == nil {
+		return &[]oscalTypes.SetParameter{parameter}
+	}
+	for i := range *setParameters {
+		if (*setParameters)[i].ParamId == parameter.ParamId {
+			(*setParameters)[i] = parameter
+			return setParameters
+		}
+	}
+	*setParameters = append(*setParameters, parameter)
+	return setParameters
+}
+
+// removeParameter removes the set-parameter with the given param id.
+func removeParameter(setParameters *[]oscalTypes.SetParameter, paramId string) *[]oscalTypes.SetParameter {
+	if setParameters == nil {
+		return nil
+	}
+	remaining := slices.DeleteFunc(*setParameters, func(parameter oscalTypes.SetParameter) bool {
+		return parameter.ParamId == paramId
+	})
+	return utils.NilIfEmpty(&remaining)
+}
+
+type modifierScope struct {
+	mappingReferences []string
+	controlIds        []string
+}
+
+// ModifierOption limits where the set-parameters of parameter modifiers are added.
+type ModifierOption func(scope *modifierScope)
+
+// ForMappingReferences only adds set-parameters to the control implementation sets of the
+// given mapping references (e.g. 800-161).
+func ForMappingReferences(referenceIds ...string) ModifierOption {
+	return func(scope *modifierScope) {
+		scope.mappingReferences = append(scope.mappingReferences, referenceIds...)
+	}
+}
+
+// ForControls adds set-parameters to the implemented requirements of the given controls instead of
+// the control implementation sets. Controls are matched by OSCAL control id or by the mapping
+// identifier before normalization.
+func ForControls(controlIds ...string) ModifierOption {
+	return func(scope *modifierScope) {
+		scope.controlIds = append(scope.controlIds, controlIds...)
+	}
+}
+
+// includesSet returns whether set-parameters apply to the control implementation set.
+func (s modifierScope) includesSet(ci oscalTypes.ControlImplementationSet, vocab vocabulary.Vocabulary) bool {
+	if len(s.mappingReferences) == 0 {
+		return true
+	}
+	return slices.Contains(s.mappingReferences, frameworkOf(ci, vocab))
+}
+
+// includesRequirement returns whether set-parameters apply to the implemented requirement.
+func (s modifierScope) includesRequirement(controlId string, normalizer normalize.Normalizer) bool {
+	return slices.ContainsFunc(s.controlIds, func(candidate string) bool {
+		return candidate == controlId || normalizer.Normalize(candidate) == controlId
+	})
+}
+
+// frameworkOf returns the mapping reference id of a control implementation set.
+func frameworkOf(ci oscalTypes.ControlImplementationSet, vocab vocabulary.Vocabulary) string {
+	prop, _ := vocab.Get(vocab.Framework, ci.Props)
+	return prop.Value
+}
@@ -0,0 +1,252 @@
+package component
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/ossf/gemara/layer2"
+	"github.com/ossf/gemara/layer3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpower432/gemara2oscal/controls"
+	"github.com/jpower432/gemara2oscal/normalize"
+)
+
+func TestDefinitionBuilder_AddParameterModifiers(t *testing.T) {
+	file, err := os.Open("./testdata/good-osps.yml")
+	require.NoError(t, err)
+
+	var catalog layer2.Catalog
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&catalog)
+	require.NoError(t, err)
+
+	const paramId = "main_branch_min_approvals"
+
+	tests := []struct {
+		name            string
+		opts            []Option
+		modifiers       []layer3.ParameterModifier
+		wantDiagnostics []controls.Diagnostic
+		wantParams      []oscalTypes.SetParameter
+	}{
+		{
+			name: "Increase strictness",
+			modifiers: []layer3.ParameterModifier{
+				{TargetId: paramId, ModType: IncreaseStrictness, Value: 2},
+			},
+			wantParams: []oscalTypes.SetParameter{{ParamId: paramId, Values: []string{"2"}}},
+		},
+		{
+			name: "Tighten below the default",
+			modifiers: []layer3.ParameterModifier{
+				{TargetId: paramId, ModType: Tighten, Value: 0},
+			},
+			wantDiagnostics: []controls.Diagnostic{
+				{Severity: controls.SeverityError, Location: paramId, Message: "tighten value 0 is less strict than the default 1"},
+			},
+		},
+		{
+			name: "Tighten with lower is stricter",
+			opts: []Option{WithParameterOrder(paramId, ParameterOrder{LowerIsStricter: true})},
+			modifiers: []layer3.ParameterModifier{
+				{TargetId: paramId, ModType: Tighten, Value: 0},
+			},
+			wantParams: []oscalTypes.SetParameter{{ParamId: paramId, Values: []string{"0"}}},
+		},
+		{
+			name: "Tighten to the default",
+			modifiers: []layer3.ParameterModifier{
+				{TargetId: paramId, ModType: Tighten, Value: 1},
+			},
+			wantDiagnostics: []controls.Diagnostic{
+				{Severity: controls.SeverityWarning, Location: paramId, Message: "tighten value 1 does not change the default"},
+			},
+			wantParams: []oscalTypes.SetParameter{{ParamId: paramId, Values: []string{"1"}}},
+		},
+		{
+			name: "Loosen without rationale",
+			modifiers: []layer3.ParameterModifier{
+				{TargetId: paramId, ModType: Loosen, Value: 0},
+			},
+			wantDiagnostics: []controls.Diagnostic{
+				{Severity: controls.SeverityError, Location: paramId, Message: "loosen requires a modification rationale"},
+			},
+		},
+		{
+			name: "Reduce strictness with rationale",
+			modifiers: []layer3.ParameterModifier{
+				{TargetId: paramId, ModType: ReduceStrictness, ModificationRationale: "Single maintainer project", Value: 0},
+			},
+			wantParams: []oscalTypes.SetParameter{{ParamId: paramId, Values: []string{"0"}}},
+		},
+		{
+			name: "Ordered values",
+			opts: []Option{WithParameterOrder(paramId, ParameterOrder{Values: []string{"none", "maintainer", "two-maintainers"}})},
+			modifiers: []layer3.ParameterModifier{
+				{TargetId: paramId, ModType: ReduceStrictness, ModificationRationale: "Default is unordered", Value: "two-maintainers"},
+			},
+			wantParams: []oscalTypes.SetParameter{{ParamId: paramId, Values: []string{"two-maintainers"}}},
+		},
+		{
+			name: "Duplicates are replaced",
+			modifiers: []layer3.ParameterModifier{
+				{TargetId: paramId, ModType: Tighten, Value: 2},
+				{TargetId: paramId, ModType: Tighten, Value: 3},
+			},
+			wantParams: []oscalTypes.SetParameter{{ParamId: paramId, Values: []string{"3"}}},
+		},
+		{
+			name: "Exclude",
+			modifiers: []layer3.ParameterModifier{
+				{TargetId: paramId, ModType: Tighten, Value: 2},
+				{TargetId: paramId, ModType: Exclude, ModificationRationale: "Not applicable"},
+			},
+		},
+		{
+			name: "Unknown parameter and modification type",
+			modifiers: []layer3.ParameterModifier{
+				{TargetId: "unknown", ModType: Tighten, Value: 2},
+				{TargetId: paramId, ModType: "relax", Value: 2},
+			},
+			wantDiagnostics: []controls.Diagnostic{
+				{Severity: controls.SeverityError, Location: "unknown", Message: "parameter is not a recommended parameter of OSPS-B"},
+				{Severity: controls.SeverityError, Location: paramId, Message: `unknown modification type "relax"`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewDefinitionBuilder("ComponentDefinition", "v0.1.0", tt.opts...).
+				AddTargetComponent("Example", "software", catalog).
+				AddParameterModifiers("OSPS-B", tt.modifiers)
+			require.Equal(t, tt.wantDiagnostics, builder.Diagnostics())
+
+			componentDefinition, err := builder.Build()
+			if slices.ContainsFunc(tt.wantDiagnostics, func(diagnostic controls.Diagnostic) bool {
+				return diagnostic.Severity == controls.SeverityError
+			}) {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			ci := (*(*componentDefinition.Components)[0].ControlImplementations)[0]
+			if tt.wantParams == nil {
+				require.Nil(t, ci.SetParameters)
+				return
+			}
+			require.Equal(t, tt.wantParams, *ci.SetParameters)
+		})
+	}
+
+	builder := NewDefinitionBuilder("ComponentDefinition", "v0.1.0").
+		AddParameterModifiers("OSPS-B", []layer3.ParameterModifier{{TargetId: paramId, ModType: Tighten, Value: 2}})
+	require.Equal(t, []controls.Diagnostic{
+		{
+			Severity: controls.SeverityError,
+			Location: "OSPS-B",
+			Message:  "parameter modifiers reference a catalog that has not been added as a target component",
+		},
+	}, builder.Diagnostics())
+}
+
+func TestDefinitionBuilder_ScopedParameterModifiers(t *testing.T) {
+	file, err := os.Open("./testdata/good-osps.yml")
+	require.NoError(t, err)
+
+	var catalog layer2.Catalog
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&catalog)
+	require.NoError(t, err)
+	catalog.Metadata.MappingReferences = append(catalog.Metadata.MappingReferences, layer2.MappingReference{
+		Id:    "PCIDSS",
+		Title: "Payment Card Industry Data Security Standard",
+	})
+
+	const paramId = "main_branch_min_approvals"
+	modifiers := []layer3.ParameterModifier{{TargetId: paramId, ModType: Tighten, Value: 2}}
+	want := []oscalTypes.SetParameter{{ParamId: paramId, Values: []string{"2"}}}
+
+	build := func(add func(builder *DefinitionBuilder)) []oscalTypes.ControlImplementationSet {
+		builder := NewDefinitionBuilder("ComponentDefinition", "v0.1.0", WithReferenceNormalizer("PCIDSS", normalize.PCI)).
+			AddTargetComponent("Example", "software", catalog)
+		add(builder)
+		require.Empty(t, builder.Diagnostics())
+		componentDefinition, err := builder.Build()
+		require.NoError(t, err)
+		ci := *(*componentDefinition.Components)[0].ControlImplementations
+		require.Len(t, ci, 2)
+		return ci
+	}
+
+	t.Run("Mapping reference", func(t *testing.T) {
+		ci := build(func(builder *DefinitionBuilder) {
+			builder.AddParameterModifiers("OSPS-B", modifiers, ForMappingReferences("800-161"))
+		})
+		require.Equal(t, want, *ci[0].SetParameters)
+		require.Nil(t, ci[1].SetParameters)
+	})
+
+	t.Run("Implemented requirements", func(t *testing.T) {
+		ci := build(func(builder *DefinitionBuilder) {
+			builder.AddParameterModifiers("OSPS-B", modifiers, ForMappingReferences("800-161"), ForControls("AC-5", "au-6"))
+		})
+		require.Nil(t, ci[0].SetParameters)
+		for _, implRequirement := range ci[0].ImplementedRequirements {
+			switch implRequirement.ControlId {
+			case "ac-5", "au-6":
+				require.Equal(t, want, *implRequirement.SetParameters)
+			default:
+				require.Nil(t, implRequirement.SetParameters)
+			}
+		}
+		for _, implRequirement := range ci[1].ImplementedRequirements {
+			require.Nil(t, implRequirement.SetParameters)
+		}
+	})
+
+	t.Run("Policy", func(t *testing.T) {
+		ci := build(func(builder *DefinitionBuilder) {
+			builder.AddPolicy(layer3.PolicyDocument{
+				ControlReferences: []layer3.Mapping{
+					{ReferenceId: "OSPS-B", ParameterModifications: []layer3.ParameterModifier{{TargetId: paramId, ModType: Tighten, Value: 3}}},
+				},
+				GuidanceReferences: []layer3.Mapping{
+					{ReferenceId: "PCIDSS", ParameterModifications: modifiers},
+				},
+			})
+		})
+		require.Equal(t, []oscalTypes.SetParameter{{ParamId: paramId, Values: []string{"3"}}}, *ci[0].SetParameters)
+		require.Equal(t, want, *ci[1].SetParameters)
+	})
+
+	t.Run("No match", func(t *testing.T) {
+		builder := NewDefinitionBuilder("ComponentDefinition", "v0.1.0").
+			AddTargetComponent("Example", "software", catalog).
+			AddParameterModifiers("OSPS-B", modifiers, ForMappingReferences("ISO-27001")).
+			AddParameterModifiers("OSPS-B", modifiers, ForControls("ZZ-1"))
+		diagnostic := controls.Diagnostic{
+			Severity: controls.SeverityWarning,
+			Location: paramId,
+			Message:  "parameter modifier of OSPS-B matches no control implementation set or implemented requirement and was not applied",
+		}
+		require.Equal(t, []controls.Diagnostic{diagnostic, diagnostic}, builder.Diagnostics())
+	})
+
+	builder := NewDefinitionBuilder("ComponentDefinition", "v0.1.0").
+		AddTargetComponent("Example", "software", catalog).
+		AddPolicy(layer3.PolicyDocument{
+			Metadata:           layer3.Metadata{Id: "POL-1"},
+			GuidanceReferences: []layer3.Mapping{{ReferenceId: "ISO-27001", ParameterModifications: modifiers}},
+		})
+	require.Equal(t, []controls.Diagnostic{
+		{
+			Severity: controls.SeverityWarning,
+			Location: "ISO-27001",
+			Message:  "policy POL-1 modifies a guidance reference that is not mapped by any target component",
+		},
+	}, builder.Diagnostics())
+}
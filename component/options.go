@@ -0,0 +1,109 @@
+package component
+
+import (
+	"time"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+	"github.com/jpower432/gemara2oscal/normalize"
+	"github.com/jpower432/gemara2oscal/vocabulary"
+)
+
+type options struct {
+	utils.Reproducibility
+	normalizers     normalize.Table
+	orders          map[string]ParameterOrder
+	strict          bool
+	mappingMetadata MappingMetadataFunc
+	minimumStrength int
+	vocabulary      vocabulary.Vocabulary
+	sources         SourceResolver
+}
+
+// Option configures a DefinitionBuilder.
+type Option func(opts *options)
+
+// WithDeterministicUUIDs makes repeated builds from the same inputs produce identical component
+// definitions. UUIDs are derived from Gemara identifiers and the last modified date is the
+// WithTimestamp time, the latest last modified date of the target catalogs or the Unix epoch.
+func WithDeterministicUUIDs() Option {
+	return func(opts *options) {
+		opts.Deterministic = true
+	}
+}
+
+// WithTimestamp sets the last modified date of the component definition in deterministic mode.
+func WithTimestamp(timestamp time.Time) Option {
+	return func(opts *options) {
+		opts.Timestamp = &timestamp
+	}
+}
+
+// WithReferenceNormalizer sets the Normalizer used to convert the guideline mapping identifiers of
+// the given mapping reference into OSCAL control ids (e.g. normalize.PCI for a PCI DSS reference).
+// The default is normalize.NIST80053.
+func WithReferenceNormalizer(referenceId string, normalizer normalize.Normalizer) Option {
+	return func(opts *options) {
+		opts.normalizers.Set(referenceId, normalizer)
+	}
+}
+
+// WithDefaultNormalizer sets the Normalizer used for mapping references without a
+// reference-specific Normalizer.
+func WithDefaultNormalizer(normalizer normalize.Normalizer) Option {
+	return func(opts *options) {
+		opts.normalizers.Default = normalizer
+	}
+}
+
+// WithParameterOrder sets how the values of a recommended parameter compare in strictness when
+// validating parameter modifiers.
+func WithParameterOrder(parameterId string, order ParameterOrder) Option {
+	return func(opts *options) {
+		if opts.orders == nil {
+			opts.orders = make(map[string]ParameterOrder)
+		}
+		opts.orders[parameterId] = order
+	}
+}
+
+// WithStrictMode fails Build on warnings (e.g. empty components or catalogs without mapping
+// references) as well as errors.
+func WithStrictMode() Option {
+	return func(opts *options) {
+		opts.strict = true
+	}
+}
+
+// WithMappingMetadata sets the function that provides the relationship, strength and remarks of
+// guideline mappings. Layer 2 mappings only list identifiers, so this metadata must be supplied
+// separately. It is recorded as implemented requirement props.
+func WithMappingMetadata(metadata MappingMetadataFunc) Option {
+	return func(opts *options) {
+		opts.mappingMetadata = metadata
+	}
+}
+
+// WithMinimumStrength excludes guideline mappings with a strength below the minimum from the control
+// implementation sets. Mappings with an unknown strength are kept. Strengths are provided by
+// WithMappingMetadata, and setting a minimum without it is reported as an error by Build.
+func WithMinimumStrength(strength int) Option {
+	return func(opts *options) {
+		opts.minimumStrength = strength
+	}
+}
+
+// WithVocabulary sets the vocabulary of the emitted props. The default is vocabulary.Trestle.
+func WithVocabulary(vocab vocabulary.Vocabulary) Option {
+	return func(opts *options) {
+		opts.vocabulary = vocab
+	}
+}
+
+// WithSourceResolver sets the SourceResolver for control implementation set sources. Without a
+// resolver, the source is the mapping reference URL. Implemented requirements with control ids that
+// are not defined in a resolved source catalog fail Build.
+func WithSourceResolver(resolver SourceResolver) Option {
+	return func(opts *options) {
+		opts.sources = resolver
+	}
+}
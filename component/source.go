@@ -0,0 +1,99 @@
+package component
+
+import (
+	"fmt"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/ossf/gemara/layer2"
+
+	"github.com/jpower432/gemara2oscal/controls"
+)
+
+// Source is the resolved source of the control implementation set of a mapping reference.
+type Source struct {
+	// Href is the location of the profile or catalog the set implements (e.g. a local file path).
+	Href string
+	// Catalog is the catalog at Href or, for a profile, the resolved profile catalog. When set,
+	// the implemented requirement control ids are checked against its controls.
+	Catalog *oscalTypes.Catalog
+}
+
+// SourceResolver resolves the source of the control implementation set of a mapping reference.
+// The second return value is false when the mapping reference has no known source.
+type SourceResolver interface {
+	Resolve(reference layer2.MappingReference) (Source, bool)
+}
+
+// SourceTable is a SourceResolver that looks up sources by mapping reference id.
+type SourceTable map[string]Source
+
+func (s SourceTable) Resolve(reference layer2.MappingReference) (Source, bool) {
+	source, found := s[reference.Id]
+	return source, found
+}
+
+// resolveSource returns the control implementation set source of a mapping reference. Without
+// a resolver the mapping reference URL is used. References the resolver does not know fall back
+// to the URL with a warning, since it is rarely a loadable OSCAL document.
+func (c *DefinitionBuilder) resolveSource(catalog layer2.Catalog, reference layer2.MappingReference) Source {
+	if c.sources == nil {
+		return Source{Href: reference.Url}
+	}
+	source, found := c.sources.Resolve(reference)
+	if !found {
+		c.diagnostics = append(c.diagnostics, controls.Diagnostic{
+			Severity: controls.SeverityWarning,
+			Location: catalog.Metadata.Id,
+			Message:  fmt.Sprintf("mapping reference %s has no resolved source, using %q", reference.Id, reference.Url),
+		})
+		return Source{Href: reference.Url}
+	}
+	return source
+}
+
+// sourceDiagnostics returns an error for every implemented requirement with a control id that is
+// not defined in the resolved source catalog.
+func sourceDiagnostics(referenceId string, source Source, ci oscalTypes.ControlImplementationSet) []controls.Diagnostic {
+	if source.Catalog == nil {
+		return nil
+	}
+	controlIds := make(map[string]bool)
+	if source.Catalog.Controls != nil {
+		addControlIds(controlIds, *source.Catalog.Controls)
+	}
+	if source.Catalog.Groups != nil {
+		addGroupControlIds(controlIds, *source.Catalog.Groups)
+	}
+
+	var diagnostics []controls.Diagnostic
+	for _, implRequirement := range ci.ImplementedRequirements {
+		if !controlIds[implRequirement.ControlId] {
+			diagnostics = append(diagnostics, controls.Diagnostic{
+				Severity: controls.SeverityError,
+				Location: implRequirement.ControlId,
+				Message:  fmt.Sprintf("control is not defined in the %s source %q", referenceId, source.Href),
+			})
+		}
+	}
+	return diagnostics
+}
+
+func addGroupControlIds(controlIds map[string]bool, groups []oscalTypes.Group) {
+	for _, group := range groups {
+		if group.Controls != nil {
+			addControlIds(controlIds, *group.Controls)
+		}
+		if group.Groups != nil {
+			addGroupControlIds(controlIds, *group.Groups)
+		}
+	}
+}
+
+func addControlIds(controlIds map[string]bool, catalogControls []oscalTypes.Control) {
+	for _, control := range catalogControls {
+		controlIds[control.ID] = true
+		if control.Controls != nil {
+			addControlIds(controlIds, *control.Controls)
+		}
+	}
+}
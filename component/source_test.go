@@ -0,0 +1,73 @@
+package component
+
+import (
+	"os"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/ossf/gemara/layer2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpower432/gemara2oscal/controls"
+)
+
+func TestDefinitionBuilder_SourceResolver(t *testing.T) {
+	file, err := os.Open("./testdata/good-osps.yml")
+	require.NoError(t, err)
+
+	var catalog layer2.Catalog
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&catalog)
+	require.NoError(t, err)
+
+	nistCatalog := &oscalTypes.Catalog{
+		Groups: &[]oscalTypes.Group{
+			{ID: "ac", Title: "Access Control", Controls: &[]oscalTypes.Control{{ID: "ac-5", Title: "Separation of Duties"}}},
+			{ID: "au", Title: "Audit and Accountability", Controls: &[]oscalTypes.Control{{ID: "au-6", Title: "Audit Record Review, Analysis, and Reporting"}}},
+			{ID: "pl", Title: "Planning", Controls: &[]oscalTypes.Control{{ID: "pl-8", Title: "Security and Privacy Architectures"}}},
+			{ID: "sa", Title: "System and Services Acquisition", Controls: &[]oscalTypes.Control{{
+				ID:       "sa-15",
+				Title:    "Development Process, Standards, and Tools",
+				Controls: &[]oscalTypes.Control{{ID: "sa-15.1", Title: "Quality Metrics"}},
+			}}},
+		},
+	}
+
+	t.Run("Resolved", func(t *testing.T) {
+		builder := NewDefinitionBuilder("ComponentDefinition", "v0.1.0", WithSourceResolver(SourceTable{"800-161": {Href: "profiles/800-161/profile.json"}}))
+		componentDefinition, err := builder.AddTargetComponent("Example", "software", catalog).Build()
+		require.NoError(t, err)
+		require.Empty(t, builder.Diagnostics())
+		err = validation.NewSchemaValidator().Validate(oscalTypes.OscalModels{ComponentDefinition: &componentDefinition})
+		require.NoError(t, err)
+		ci := (*(*componentDefinition.Components)[0].ControlImplementations)[0]
+		require.Equal(t, "profiles/800-161/profile.json", ci.Source)
+	})
+
+	t.Run("Unknown control ids", func(t *testing.T) {
+		builder := NewDefinitionBuilder("ComponentDefinition", "v0.1.0", WithSourceResolver(SourceTable{"800-161": {Href: "catalogs/800-53.json", Catalog: nistCatalog}}))
+		_, err := builder.AddTargetComponent("Example", "software", catalog).Build()
+		var buildErr *BuildError
+		require.ErrorAs(t, err, &buildErr)
+		require.Equal(t, []controls.Diagnostic{{
+			Severity: controls.SeverityError,
+			Location: "sr-3",
+			Message:  `control is not defined in the 800-161 source "catalogs/800-53.json"`,
+		}}, buildErr.Diagnostics)
+	})
+
+	t.Run("Unresolved", func(t *testing.T) {
+		builder := NewDefinitionBuilder("ComponentDefinition", "v0.1.0", WithSourceResolver(SourceTable{}))
+		componentDefinition, err := builder.AddTargetComponent("Example", "software", catalog).Build()
+		require.NoError(t, err)
+		require.Equal(t, []controls.Diagnostic{{
+			Severity: controls.SeverityWarning,
+			Location: catalog.Metadata.Id,
+			Message:  `mapping reference 800-161 has no resolved source, using "https://csrc.nist.gov/pubs/sp/800/161/r1/upd1/final"`,
+		}}, builder.Diagnostics())
+		ci := (*(*componentDefinition.Components)[0].ControlImplementations)[0]
+		require.Equal(t, "https://csrc.nist.gov/pubs/sp/800/161/r1/upd1/final", ci.Source)
+	})
+}
@@ -0,0 +1,57 @@
+package component
+
+import (
+	"strconv"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+	"github.com/jpower432/gemara2oscal/mapping"
+	"github.com/jpower432/gemara2oscal/vocabulary"
+)
+
+// Implemented requirement props describing the guideline mapping of a rule. The prop group is
+// the rule id (as an OSCAL token) so the metadata of each rule can be told apart.
+const (
+	MappingRelationshipProp = "mapping-relationship"
+	MappingStrengthProp     = "mapping-strength"
+)
+
+// MappingMetadata describes how a Layer 2 control relates to a mapped identifier.
+type MappingMetadata struct {
+	Relationship mapping.Relationship
+	// Strength is how strongly the control satisfies the identifier, from 1 (weak) to 10 (full).
+	// Zero means the strength is unknown.
+	Strength int
+	// Remarks are added to the implemented requirement remarks as a paragraph prefixed with the
+	// rule id, like the assessment requirement recommendation.
+	Remarks string
+}
+
+// MappingMetadataFunc returns the metadata for the mapping of a Layer 2 control to an identifier
+// of a mapping reference. The second return value is false when there is no metadata.
+type MappingMetadataFunc func(controlId, referenceId, identifier string) (MappingMetadata, bool)
+
+// props returns the implemented requirement props for the mapping metadata of a rule.
+func (m MappingMetadata) props(ruleId string, vocab vocabulary.Vocabulary) []oscalTypes.Property {
+	var props []oscalTypes.Property
+	addProp := func(name, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			prop := vocab.Prop(name, value)
+			prop.Group = utils.ToToken(ruleId)
+			props = append(props, prop)
+		}
+	}
+	addProp(MappingRelationshipProp, string(m.Relationship))
+	if m.Strength > 0 {
+		addProp(MappingStrengthProp, strconv.Itoa(m.Strength))
+	}
+	return props
+}
+
+// remarks returns the mapping remarks of a rule as a single remarks paragraph, so it is refreshed
+// with the other generated paragraphs when an existing component definition is updated.
+func (m MappingMetadata) remarks(ruleId string) string {
+	return requirementParagraph(ruleId, strings.Join(strings.Fields(m.Remarks), " "))
+}
@@ -0,0 +1,86 @@
+package component
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/ossf/gemara/layer2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpower432/gemara2oscal/controls"
+	"github.com/jpower432/gemara2oscal/mapping"
+)
+
+func TestDefinitionBuilder_MappingMetadata(t *testing.T) {
+	file, err := os.Open("./testdata/good-osps.yml")
+	require.NoError(t, err)
+
+	var catalog layer2.Catalog
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&catalog)
+	require.NoError(t, err)
+
+	metadata := func(controlId, referenceId, identifier string) (MappingMetadata, bool) {
+		require.Equal(t, "OSPS-QA-07", controlId)
+		switch identifier {
+		case "AC-5":
+			return MappingMetadata{Relationship: mapping.SubsetOf, Strength: 8, Remarks: "Separation of duties through review"}, true
+		case "AU-6":
+			return MappingMetadata{Relationship: mapping.IntersectsWith, Strength: 2}, true
+		default:
+			return MappingMetadata{}, false
+		}
+	}
+
+	build := func(t *testing.T, opts ...Option) []oscalTypes.ImplementedRequirementControlImplementation {
+		componentDefinition, err := NewDefinitionBuilder("ComponentDefinition", "v0.1.0", opts...).
+			AddTargetComponent("Example", "software", catalog).
+			Build()
+		require.NoError(t, err)
+		err = validation.NewSchemaValidator().Validate(oscalTypes.OscalModels{ComponentDefinition: &componentDefinition})
+		require.NoError(t, err)
+		return (*(*componentDefinition.Components)[0].ControlImplementations)[0].ImplementedRequirements
+	}
+
+	t.Run("Props", func(t *testing.T) {
+		implRequirements := build(t, WithMappingMetadata(metadata))
+		require.Len(t, implRequirements, 5)
+		require.Equal(t, "ac-5", implRequirements[0].ControlId)
+		require.Equal(t, []oscalTypes.Property{
+			{Name: extensions.RuleIdProp, Value: "OSPS-QA-07.01", Ns: extensions.TrestleNameSpace},
+			{Name: MappingRelationshipProp, Value: "subset-of", Ns: extensions.TrestleNameSpace, Group: "osps-qa-07.01"},
+			{Name: MappingStrengthProp, Value: "8", Ns: extensions.TrestleNameSpace, Group: "osps-qa-07.01"},
+		}, *implRequirements[0].Props)
+		require.True(t, strings.HasSuffix(implRequirements[0].Remarks, "\n\nOSPS-QA-07.01: Separation of duties through review"))
+		require.Equal(t, "pl-8", implRequirements[2].ControlId)
+		require.Len(t, *implRequirements[2].Props, 1)
+	})
+
+	t.Run("Minimum strength", func(t *testing.T) {
+		implRequirements := build(t, WithMappingMetadata(metadata), WithMinimumStrength(5))
+		var controlIds []string
+		for _, implRequirement := range implRequirements {
+			controlIds = append(controlIds, implRequirement.ControlId)
+		}
+		// au-6 is a weak mapping, mappings without a strength are kept
+		require.Equal(t, []string{"ac-5", "pl-8", "sa-15", "sr-3"}, controlIds)
+	})
+
+	t.Run("Minimum strength without metadata", func(t *testing.T) {
+		_, err := NewDefinitionBuilder("ComponentDefinition", "v0.1.0", WithMinimumStrength(5)).
+			AddTargetComponent("Example", "software", catalog).
+			Build()
+		var buildErr *BuildError
+		require.ErrorAs(t, err, &buildErr)
+		require.Equal(t, []controls.Diagnostic{{
+			Severity: controls.SeverityError,
+			Location: "ComponentDefinition",
+			Message:  "minimum mapping strength is set without mapping metadata, so no mapping is excluded",
+		}}, buildErr.Diagnostics)
+	})
+}
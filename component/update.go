@@ -0,0 +1,263 @@
+package component
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+	"github.com/jpower432/gemara2oscal/vocabulary"
+)
+
+// NewDefinitionBuilderFrom returns a DefinitionBuilder that updates an existing component definition
+// instead of creating a new one. Build matches the generated components to existing components by
+// UUID or by title and type, control implementation sets by mapping reference and implemented
+// requirements and statements by control and statement id. Matched elements keep their UUIDs, remarks
+// and other content that is not generated from Gemara inputs (e.g. links and responsible roles), while
+// generated content is replaced. Props outside the vocabulary and Gemara namespaces are hand-written
+// and kept after the generated props. Remarks paragraphs prefixed with the id of a rule of the
+// element (e.g. "OSPS-QA-07.01: ...") are generated and replaced, and other paragraphs are kept
+// after them. Statements that are no longer mapped are removed.
+// Implemented requirements and control implementation sets that are no longer generated are removed.
+// Existing components that are not added to the builder are kept unchanged.
+func NewDefinitionBuilderFrom(existing oscalTypes.ComponentDefinition, opts ...Option) *DefinitionBuilder {
+	builder := NewDefinitionBuilder(existing.Metadata.Title, existing.Metadata.Version, opts...)
+	builder.existing = &existing
+	return builder
+}
+
+// mergeDefinition merges a generated component definition into an existing one. The last modified
+// date is only updated, to the generated last modified date, when the components changed other than
+// in UUIDs.
+func mergeDefinition(existing, generated oscalTypes.ComponentDefinition, vocab vocabulary.Vocabulary) oscalTypes.ComponentDefinition {
+	merged := existing
+	merged.Metadata.Title = generated.Metadata.Title
+	merged.Metadata.Version = generated.Metadata.Version
+
+	var generatedComponents []oscalTypes.DefinedComponent
+	if generated.Components != nil {
+		generatedComponents = *generated.Components
+	}
+	matched := make([]bool, len(generatedComponents))
+
+	var components []oscalTypes.DefinedComponent
+	if existing.Components != nil {
+		for _, component := range *existing.Components {
+			index := -1
+			for i, candidate := range generatedComponents {
+				if !matched[i] && sameComponent(component, candidate) {
+					index = i
+					break
+				}
+			}
+			if index < 0 {
+				components = append(components, component)
+				continue
+			}
+			matched[index] = true
+			components = append(components, mergeComponent(component, generatedComponents[index], vocab))
+		}
+	}
+	for i, component := range generatedComponents {
+		if !matched[i] {
+			components = append(components, component)
+		}
+	}
+	merged.Components = utils.NilIfEmpty(&components)
+
+	if !sameContent(existing.Components, merged.Components) {
+		merged.Metadata.LastModified = generated.Metadata.LastModified
+	}
+	return merged
+}
+
+// sameContent returns whether the components are equal when UUIDs are ignored, since the UUIDs of
+// generated elements are random unless deterministic UUIDs are used.
+func sameContent(existing, merged *[]oscalTypes.DefinedComponent) bool {
+	existingContent, err := withoutUUIDs(existing)
+	if err != nil {
+		return false
+	}
+	mergedContent, err := withoutUUIDs(merged)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(existingContent, mergedContent)
+}
+
+// withoutUUIDs returns the JSON representation of the value without uuid fields.
+func withoutUUIDs(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var content any
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+	var strip func(node any)
+	strip = func(node any) {
+		switch typed := node.(type) {
+		case map[string]any:
+			delete(typed, "uuid")
+			for _, child := range typed {
+				strip(child)
+			}
+		case []any:
+			for _, child := range typed {
+				strip(child)
+			}
+		}
+	}
+	strip(content)
+	return content, nil
+}
+
+func sameComponent(existing, generated oscalTypes.DefinedComponent) bool {
+	return existing.UUID == generated.UUID || (existing.Title == generated.Title && existing.Type == generated.Type)
+}
+
+func mergeComponent(existing, generated oscalTypes.DefinedComponent, vocab vocabulary.Vocabulary) oscalTypes.DefinedComponent {
+	merged := existing
+	merged.Props = mergeProps(existing.Props, generated.Props, vocab)
+	if generated.Description != "" {
+		merged.Description = generated.Description
+	}
+
+	existingSets := make(map[string]oscalTypes.ControlImplementationSet)
+	if existing.ControlImplementations != nil {
+		for _, ci := range *existing.ControlImplementations {
+			existingSets[frameworkOf(ci, vocab)] = ci
+		}
+	}
+
+	var controlImplementations []oscalTypes.ControlImplementationSet
+	if generated.ControlImplementations != nil {
+		for _, ci := range *generated.ControlImplementations {
+			if existingSet, found := existingSets[frameworkOf(ci, vocab)]; found {
+				ci = mergeImplementationSet(existingSet, ci, vocab)
+			}
+			controlImplementations = append(controlImplementations, ci)
+		}
+	}
+	merged.ControlImplementations = utils.NilIfEmpty(&controlImplementations)
+	return merged
+}
+
+func mergeImplementationSet(existing, generated oscalTypes.ControlImplementationSet, vocab vocabulary.Vocabulary) oscalTypes.ControlImplementationSet {
+	merged := generated
+	merged.UUID = existing.UUID
+	merged.Links = existing.Links
+	if merged.Description == "" {
+		merged.Description = existing.Description
+	}
+
+	existingRequirements := make(map[string]oscalTypes.ImplementedRequirementControlImplementation)
+	for _, implRequirement := range existing.ImplementedRequirements {
+		existingRequirements[implRequirement.ControlId] = implRequirement
+	}
+
+	merged.ImplementedRequirements = make([]oscalTypes.ImplementedRequirementControlImplementation, 0, len(generated.ImplementedRequirements))
+	for _, implRequirement := range generated.ImplementedRequirements {
+		if existingRequirement, found := existingRequirements[implRequirement.ControlId]; found {
+			implRequirement = mergeImplementedRequirement(existingRequirement, implRequirement, vocab)
+		}
+		merged.ImplementedRequirements = append(merged.ImplementedRequirements, implRequirement)
+	}
+	return merged
+}
+
+func mergeImplementedRequirement(existing, generated oscalTypes.ImplementedRequirementControlImplementation, vocab vocabulary.Vocabulary) oscalTypes.ImplementedRequirementControlImplementation {
+	// Statement rules are included so their paragraphs are removed from the implemented requirement
+	// remarks of definitions where they were repeated there
+	ruleIds := make(map[string]bool)
+	for _, implRequirement := range []oscalTypes.ImplementedRequirementControlImplementation{existing, generated} {
+		addRuleIds(ruleIds, implRequirement.Props, vocab)
+		if implRequirement.Statements != nil {
+			for _, statement := range *implRequirement.Statements {
+				addRuleIds(ruleIds, statement.Props, vocab)
+			}
+		}
+	}
+
+	merged := existing
+	merged.Props = mergeProps(existing.Props, generated.Props, vocab)
+	merged.SetParameters = generated.SetParameters
+	merged.Remarks = mergeRemarks(existing.Remarks, generated.Remarks, ruleIds)
+	if generated.Description != "" {
+		merged.Description = generated.Description
+	}
+
+	// Statements are always rebuilt from the generated statements, so statements that are no
+	// longer mapped are removed.
+	existingStatements := make(map[string]oscalTypes.ControlStatementImplementation)
+	if existing.Statements != nil {
+		for _, statement := range *existing.Statements {
+			existingStatements[statement.StatementId] = statement
+		}
+	}
+	var statements []oscalTypes.ControlStatementImplementation
+	if generated.Statements != nil {
+		for _, statement := range *generated.Statements {
+			if existingStatement, found := existingStatements[statement.StatementId]; found {
+				statementRuleIds := make(map[string]bool)
+				addRuleIds(statementRuleIds, existingStatement.Props, vocab)
+				addRuleIds(statementRuleIds, statement.Props, vocab)
+
+				statement.UUID = existingStatement.UUID
+				statement.Props = mergeProps(existingStatement.Props, statement.Props, vocab)
+				statement.Links = existingStatement.Links
+				statement.ResponsibleRoles = existingStatement.ResponsibleRoles
+				statement.Remarks = mergeRemarks(existingStatement.Remarks, statement.Remarks, statementRuleIds)
+			}
+			statements = append(statements, statement)
+		}
+	}
+	merged.Statements = utils.NilIfEmpty(&statements)
+	return merged
+}
+
+// mergeProps returns the generated props followed by the existing props that are not in the
+// vocabulary or Gemara namespace. Props in those namespaces are generated, so they are replaced.
+func mergeProps(existing, generated *[]oscalTypes.Property, vocab vocabulary.Vocabulary) *[]oscalTypes.Property {
+	var props []oscalTypes.Property
+	if generated != nil {
+		props = append(props, *generated...)
+	}
+	if existing != nil {
+		for _, prop := range *existing {
+			if prop.Ns != vocab.Namespace && prop.Ns != vocabulary.GemaraNameSpace {
+				props = append(props, prop)
+			}
+		}
+	}
+	return utils.NilIfEmpty(&props)
+}
+
+// mergeRemarks replaces the generated paragraphs of the existing remarks with the generated remarks.
+// Paragraphs prefixed with one of the rule ids (see requirementParagraph) are generated, so they are
+// refreshed on every build. Other paragraphs are hand-written and kept after the generated ones.
+func mergeRemarks(existing, generated string, ruleIds map[string]bool) string {
+	remarks := generated
+	for _, paragraph := range strings.Split(existing, "\n\n") {
+		if ruleId, _, found := strings.Cut(paragraph, ": "); found && ruleIds[ruleId] {
+			continue
+		}
+		remarks = appendParagraph(remarks, paragraph)
+	}
+	return remarks
+}
+
+// addRuleIds adds the values of the rule id props to ruleIds.
+func addRuleIds(ruleIds map[string]bool, props *[]oscalTypes.Property, vocab vocabulary.Vocabulary) {
+	if props == nil {
+		return
+	}
+	for _, prop := range *props {
+		if prop.Name == vocab.RuleId && prop.Ns == vocab.Namespace {
+			ruleIds[prop.Value] = true
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package component
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/ossf/gemara/layer2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDefinitionBuilderFrom(t *testing.T) {
+	file, err := os.Open("./testdata/good-osps.yml")
+	require.NoError(t, err)
+
+	var catalog layer2.Catalog
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&catalog)
+	require.NoError(t, err)
+
+	existing, err := NewDefinitionBuilder("ComponentDefinition", "v0.1.0").
+		AddTargetComponent("Example", "software", catalog).
+		Build()
+	require.NoError(t, err)
+
+	// Hand-written content
+	component := &(*existing.Components)[0]
+	component.Remarks = "Maintained by the platform team"
+	ci := &(*component.ControlImplementations)[0]
+	ci.ImplementedRequirements[0].Remarks = appendParagraph(ci.ImplementedRequirements[0].Remarks, "Reviewed quarterly")
+	*existing.Components = append(*existing.Components, oscalTypes.DefinedComponent{
+		UUID:        "8a3ff1c6-5c33-4b5e-9a43-0bd5e2e8f3a1",
+		Type:        "service",
+		Title:       "Hand-written",
+		Description: "Not generated from Gemara",
+	})
+
+	t.Run("Unchanged", func(t *testing.T) {
+		updated, err := NewDefinitionBuilderFrom(existing).
+			AddTargetComponent("Example", "software", catalog).
+			Build()
+		require.NoError(t, err)
+		require.Equal(t, existing, updated)
+	})
+
+	t.Run("Changed", func(t *testing.T) {
+		changed := catalog
+		changed.ControlFamilies = []layer2.ControlFamily{catalog.ControlFamilies[0]}
+		changed.ControlFamilies[0].Controls = []layer2.Control{catalog.ControlFamilies[0].Controls[0]}
+		control := &changed.ControlFamilies[0].Controls[0]
+		control.GuidelineMappings = []layer2.Mapping{{ReferenceId: "800-161", Identifiers: []string{"AC-5", "CM-3"}}}
+
+		updated, err := NewDefinitionBuilderFrom(existing).
+			AddTargetComponent("Example", "software", changed).
+			Build()
+		require.NoError(t, err)
+
+		require.Equal(t, existing.UUID, updated.UUID)
+		require.Len(t, *updated.Components, 2)
+		updatedComponent := (*updated.Components)[0]
+		require.Equal(t, component.UUID, updatedComponent.UUID)
+		require.Equal(t, "Maintained by the platform team", updatedComponent.Remarks)
+		require.Equal(t, (*existing.Components)[1], (*updated.Components)[1])
+
+		updatedCI := (*updatedComponent.ControlImplementations)[0]
+		require.Equal(t, ci.UUID, updatedCI.UUID)
+		require.Len(t, updatedCI.ImplementedRequirements, 2)
+		require.Equal(t, ci.ImplementedRequirements[0], updatedCI.ImplementedRequirements[0])
+		require.Equal(t, "cm-3", updatedCI.ImplementedRequirements[1].ControlId)
+		require.NotEqual(t, existing.Metadata.LastModified, updated.Metadata.LastModified)
+
+		err = validation.NewSchemaValidator().Validate(oscalTypes.OscalModels{ComponentDefinition: &updated})
+		require.NoError(t, err)
+	})
+
+	t.Run("Refreshed remarks", func(t *testing.T) {
+		changed := catalog
+		changed.ControlFamilies = slices.Clone(catalog.ControlFamilies)
+		changed.ControlFamilies[0].Controls = slices.Clone(catalog.ControlFamilies[0].Controls)
+		control := &changed.ControlFamilies[0].Controls[0]
+		control.AssessmentRequirements = slices.Clone(control.AssessmentRequirements)
+		control.AssessmentRequirements[0].Recommendation = "Require a reviewer in the branch protection rules."
+
+		timestamp := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+		updated, err := NewDefinitionBuilderFrom(existing, WithDeterministicUUIDs(), WithTimestamp(timestamp)).
+			AddTargetComponent("Example", "software", changed).
+			Build()
+		require.NoError(t, err)
+		implRequirement := (*(*updated.Components)[0].ControlImplementations)[0].ImplementedRequirements[0]
+		require.Equal(t, "OSPS-QA-07.01: Require a reviewer in the branch protection rules.\n\nReviewed quarterly", implRequirement.Remarks)
+		require.Equal(t, timestamp, updated.Metadata.LastModified)
+
+		// Rebuilding from the updated definition with random UUIDs changes nothing
+		again, err := NewDefinitionBuilderFrom(updated).
+			AddTargetComponent("Example", "software", changed).
+			Build()
+		require.NoError(t, err)
+		require.Equal(t, updated, again)
+	})
+
+	t.Run("Removed statement", func(t *testing.T) {
+		mapped := catalog
+		mapped.ControlFamilies = []layer2.ControlFamily{catalog.ControlFamilies[0]}
+		mapped.ControlFamilies[0].Controls = []layer2.Control{catalog.ControlFamilies[0].Controls[0]}
+		control := &mapped.ControlFamilies[0].Controls[0]
+		control.GuidelineMappings = []layer2.Mapping{{ReferenceId: "800-161", Identifiers: []string{"AC-5", "AC-5(a)"}}}
+
+		withStatement, err := NewDefinitionBuilder("ComponentDefinition", "v0.1.0").
+			AddTargetComponent("Example", "software", mapped).
+			Build()
+		require.NoError(t, err)
+		handWritten := oscalTypes.Property{Name: "owner", Value: "platform-team", Ns: "https://example.com/ns/oscal"}
+		statementComponent := &(*withStatement.Components)[0]
+		*statementComponent.Props = append(*statementComponent.Props, handWritten)
+		implRequirement := (*statementComponent.ControlImplementations)[0].ImplementedRequirements[0]
+		require.NotNil(t, implRequirement.Statements)
+		require.Equal(t, "ac-5_smt.a", (*implRequirement.Statements)[0].StatementId)
+
+		unmapped := mapped
+		unmapped.ControlFamilies = slices.Clone(mapped.ControlFamilies)
+		unmapped.ControlFamilies[0].Controls = slices.Clone(mapped.ControlFamilies[0].Controls)
+		unmapped.ControlFamilies[0].Controls[0].GuidelineMappings = []layer2.Mapping{{ReferenceId: "800-161", Identifiers: []string{"AC-5"}}}
+
+		updated, err := NewDefinitionBuilderFrom(withStatement).
+			AddTargetComponent("Example", "software", unmapped).
+			Build()
+		require.NoError(t, err)
+		updatedComponent := (*updated.Components)[0]
+		require.Contains(t, *updatedComponent.Props, handWritten)
+		updatedRequirement := (*updatedComponent.ControlImplementations)[0].ImplementedRequirements[0]
+		require.Equal(t, implRequirement.UUID, updatedRequirement.UUID)
+		require.Nil(t, updatedRequirement.Statements)
+	})
+}
@@ -0,0 +1,165 @@
+package component
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/ossf/gemara/layer2"
+
+	"github.com/jpower432/gemara2oscal/controls"
+	"github.com/jpower432/gemara2oscal/normalize"
+	"github.com/jpower432/gemara2oscal/vocabulary"
+)
+
+// BuildError is returned by Build when the component definition is invalid or, in strict mode,
+// degraded. It holds every issue found while building.
+type BuildError struct {
+	Diagnostics []controls.Diagnostic
+}
+
+func (b *BuildError) Error() string {
+	issues := make([]string, 0, len(b.Diagnostics))
+	for _, diagnostic := range b.Diagnostics {
+		issues = append(issues, diagnostic.String())
+	}
+	return fmt.Sprintf("invalid component definition: %s", strings.Join(issues, "; "))
+}
+
+// catalogDiagnostics returns the issues with a Layer 2 catalog added as a target component.
+func (c *DefinitionBuilder) catalogDiagnostics(catalog layer2.Catalog) []controls.Diagnostic {
+	var diagnostics []controls.Diagnostic
+	if len(catalog.Metadata.MappingReferences) == 0 {
+		diagnostics = append(diagnostics, controls.Diagnostic{
+			Severity: controls.SeverityWarning,
+			Location: catalog.Metadata.Id,
+			Message:  "catalog has no mapping references, no control implementations are generated",
+		})
+	}
+
+	requirements := make(map[string]bool)
+	for _, family := range catalog.ControlFamilies {
+		for _, control := range family.Controls {
+			for _, requirement := range control.AssessmentRequirements {
+				if requirements[requirement.Id] {
+					diagnostics = append(diagnostics, controls.Diagnostic{
+						Severity: controls.SeverityError,
+						Location: requirement.Id,
+						Message:  fmt.Sprintf("assessment requirement is defined more than once in %s", catalog.Metadata.Id),
+					})
+				}
+				requirements[requirement.Id] = true
+			}
+		}
+	}
+
+	for _, collisionErr := range c.mappingCollisions(catalog) {
+		for _, collision := range collisionErr.Collisions {
+			diagnostics = append(diagnostics, controls.Diagnostic{
+				Severity: controls.SeverityError,
+				Location: collision.OSCALId,
+				Message:  fmt.Sprintf("%s identifiers %s normalize to the same OSCAL id", collisionErr.Scope, strings.Join(collision.SourceIds, ", ")),
+			})
+		}
+	}
+	return diagnostics
+}
+
+// mappingCollisions returns the guideline mapping identifiers that normalize to the same OSCAL
+// control id, by mapping reference.
+func (c *DefinitionBuilder) mappingCollisions(catalog layer2.Catalog) []*normalize.CollisionError {
+	identifiers := make(map[string][]string)
+	var references []string
+	for _, family := range catalog.ControlFamilies {
+		for _, control := range family.Controls {
+			for _, mapping := range control.GuidelineMappings {
+				if _, found := identifiers[mapping.ReferenceId]; !found {
+					references = append(references, mapping.ReferenceId)
+				}
+				identifiers[mapping.ReferenceId] = append(identifiers[mapping.ReferenceId], mapping.Identifiers...)
+			}
+		}
+	}
+
+	var collisions []*normalize.CollisionError
+	for _, referenceId := range references {
+		if err := normalize.Check(referenceId, c.normalizers.For(referenceId), identifiers[referenceId]); err != nil {
+			collisions = append(collisions, err.(*normalize.CollisionError))
+		}
+	}
+	return collisions
+}
+
+// componentDiagnostics returns the issues found across the components of the definition: rules
+// defined by more than one target component, checks for rules that no target component defines
+// and components without rules or checks.
+func componentDiagnostics(targets, validations []oscalTypes.DefinedComponent, vocab vocabulary.Vocabulary) []controls.Diagnostic {
+	var diagnostics []controls.Diagnostic
+	rules := make(map[string]string)
+	for _, component := range targets {
+		ruleIds := ruleIdsOf(component, vocab)
+		if len(ruleIds) == 0 {
+			diagnostics = append(diagnostics, controls.Diagnostic{
+				Severity: controls.SeverityWarning,
+				Location: component.Title,
+				Message:  "component has no rules",
+			})
+			continue
+		}
+		if component.ControlImplementations == nil {
+			diagnostics = append(diagnostics, controls.Diagnostic{
+				Severity: controls.SeverityWarning,
+				Location: component.Title,
+				Message:  "component has no control implementations",
+			})
+		}
+		for _, ruleId := range ruleIds {
+			if owner, found := rules[ruleId]; found {
+				diagnostics = append(diagnostics, controls.Diagnostic{
+					Severity: controls.SeverityError,
+					Location: ruleId,
+					Message:  fmt.Sprintf("rule is defined by components %s and %s", owner, component.Title),
+				})
+				continue
+			}
+			rules[ruleId] = component.Title
+		}
+	}
+
+	for _, component := range validations {
+		ruleIds := ruleIdsOf(component, vocab)
+		if len(ruleIds) == 0 {
+			diagnostics = append(diagnostics, controls.Diagnostic{
+				Severity: controls.SeverityWarning,
+				Location: component.Title,
+				Message:  "component has no checks",
+			})
+			continue
+		}
+		for _, ruleId := range ruleIds {
+			if _, found := rules[ruleId]; !found {
+				diagnostics = append(diagnostics, controls.Diagnostic{
+					Severity: controls.SeverityError,
+					Location: ruleId,
+					Message:  fmt.Sprintf("component %s has a check for a rule that no target component defines", component.Title),
+				})
+			}
+		}
+	}
+	return diagnostics
+}
+
+// ruleIdsOf returns the distinct rule ids of a component in order.
+func ruleIdsOf(component oscalTypes.DefinedComponent, vocab vocabulary.Vocabulary) []string {
+	if component.Props == nil {
+		return nil
+	}
+	var ruleIds []string
+	for _, prop := range *component.Props {
+		if prop.Name == vocab.RuleId && prop.Ns == vocab.Namespace && !slices.Contains(ruleIds, prop.Value) {
+			ruleIds = append(ruleIds, prop.Value)
+		}
+	}
+	return ruleIds
+}
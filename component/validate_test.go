@@ -0,0 +1,163 @@
+package component
+
+import (
+	"os"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/ossf/gemara/layer2"
+	"github.com/ossf/gemara/layer4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpower432/gemara2oscal/controls"
+)
+
+func TestDefinitionBuilder_BuildErrors(t *testing.T) {
+	load := func(t *testing.T) layer2.Catalog {
+		file, err := os.Open("./testdata/good-osps.yml")
+		require.NoError(t, err)
+
+		var catalog layer2.Catalog
+		decoder := yaml.NewDecoder(file)
+		err = decoder.Decode(&catalog)
+		require.NoError(t, err)
+		return catalog
+	}
+
+	evaluation := func(requirementId string) []layer4.ControlEvaluation {
+		return []layer4.ControlEvaluation{
+			{
+				Control_Id: "OSPS-QA-07",
+				Assessments: []*layer4.Assessment{
+					{
+						Requirement_Id: requirementId,
+						Methods:        []layer4.AssessmentMethod{{Name: "my-check-id", Description: "My method"}},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		opts            []Option
+		build           func(t *testing.T, builder *DefinitionBuilder)
+		wantErr         bool
+		wantDiagnostics []controls.Diagnostic
+	}{
+		{
+			name: "Valid",
+			build: func(t *testing.T, builder *DefinitionBuilder) {
+				builder.AddTargetComponent("Example", "software", load(t)).
+					AddValidationComponent("myvalidator", evaluation("OSPS-QA-07.01"))
+			},
+		},
+		{
+			name: "Dangling check",
+			build: func(t *testing.T, builder *DefinitionBuilder) {
+				builder.AddTargetComponent("Example", "software", load(t)).
+					AddValidationComponent("myvalidator", evaluation("OSPS-QA-07.99"))
+			},
+			wantErr: true,
+			wantDiagnostics: []controls.Diagnostic{
+				{Severity: controls.SeverityError, Location: "OSPS-QA-07.99", Message: "component myvalidator has a check for a rule that no target component defines"},
+			},
+		},
+		{
+			name: "Duplicate requirement",
+			build: func(t *testing.T, builder *DefinitionBuilder) {
+				catalog := load(t)
+				control := &catalog.ControlFamilies[0].Controls[0]
+				control.AssessmentRequirements = append(control.AssessmentRequirements, control.AssessmentRequirements[0])
+				builder.AddTargetComponent("Example", "software", catalog)
+			},
+			wantErr: true,
+			wantDiagnostics: []controls.Diagnostic{
+				{Severity: controls.SeverityError, Location: "OSPS-QA-07.01", Message: "assessment requirement is defined more than once in OSPS-B"},
+			},
+		},
+		{
+			name: "Rule defined by two components",
+			build: func(t *testing.T, builder *DefinitionBuilder) {
+				copied := load(t)
+				copied.Metadata.Id = "OSPS-B-COPY"
+				builder.AddTargetComponent("Example", "software", load(t)).
+					AddTargetComponent("Copy", "software", copied)
+			},
+			wantErr: true,
+			wantDiagnostics: []controls.Diagnostic{
+				{Severity: controls.SeverityError, Location: "OSPS-QA-07.01", Message: "rule is defined by components Example and Copy"},
+			},
+		},
+		{
+			name: "Unknown catalog",
+			build: func(t *testing.T, builder *DefinitionBuilder) {
+				builder.AddParameterModifiers("OSPS-B", nil)
+			},
+			wantErr: true,
+			wantDiagnostics: []controls.Diagnostic{
+				{Severity: controls.SeverityError, Location: "OSPS-B", Message: "parameter modifiers reference a catalog that has not been added as a target component"},
+			},
+		},
+		{
+			name: "Degraded",
+			build: func(t *testing.T, builder *DefinitionBuilder) {
+				catalog := load(t)
+				catalog.Metadata.MappingReferences = nil
+				builder.AddTargetComponent("Example", "software", catalog)
+			},
+		},
+		{
+			name: "Degraded in strict mode",
+			opts: []Option{WithStrictMode()},
+			build: func(t *testing.T, builder *DefinitionBuilder) {
+				catalog := load(t)
+				catalog.Metadata.MappingReferences = nil
+				builder.AddTargetComponent("Example", "software", catalog).
+					AddValidationComponent("myvalidator", nil)
+			},
+			wantErr: true,
+			wantDiagnostics: []controls.Diagnostic{
+				{Severity: controls.SeverityWarning, Location: "OSPS-B", Message: "catalog has no mapping references, no control implementations are generated"},
+				{Severity: controls.SeverityWarning, Location: "Example", Message: "component has no control implementations"},
+				{Severity: controls.SeverityWarning, Location: "myvalidator", Message: "component has no checks"},
+			},
+		},
+		{
+			name: "Empty component in strict mode",
+			opts: []Option{WithStrictMode()},
+			build: func(t *testing.T, builder *DefinitionBuilder) {
+				builder.AddTargetComponent("Example", "software", load(t), WithApplicability("Maturity1"))
+			},
+			wantErr: true,
+			wantDiagnostics: []controls.Diagnostic{
+				{Severity: controls.SeverityWarning, Location: "Example", Message: "component has no rules"},
+			},
+		},
+		{
+			name: "Unknown applicability category",
+			build: func(t *testing.T, builder *DefinitionBuilder) {
+				builder.AddTargetComponent("Example", "software", load(t), WithApplicability("Maturity9"))
+			},
+			wantErr: true,
+			wantDiagnostics: []controls.Diagnostic{
+				{Severity: controls.SeverityError, Location: "Example", Message: `applicability category "Maturity9" is not defined in OSPS-B`},
+				{Severity: controls.SeverityWarning, Location: "Example", Message: "component has no rules"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewDefinitionBuilder("ComponentDefinition", "v0.1.0", tt.opts...)
+			tt.build(t, builder)
+			_, err := builder.Build()
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			var buildErr *BuildError
+			require.ErrorAs(t, err, &buildErr)
+			require.Equal(t, tt.wantDiagnostics, buildErr.Diagnostics)
+		})
+	}
+}
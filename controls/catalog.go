@@ -3,61 +3,66 @@ package controls
 import (
 	"fmt"
 	"strings"
-	"time"
 
-	"github.com/defenseunicorns/go-oscal/src/pkg/uuid"
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
-	"github.com/oscal-compass/oscal-sdk-go/extensions"
 	"github.com/oscal-compass/oscal-sdk-go/models"
 	"github.com/ossf/gemara/layer1"
 
 	"github.com/jpower432/gemara2oscal/internal/utils"
+	"github.com/jpower432/gemara2oscal/normalize"
 )
 
-// TODO: Add ToResolvedCatalog where the shared guidelines are fully resolved
+// UnknownReferenceError is returned by ToCatalog when guidelines reference resources that are
+// not in the document metadata. It holds a diagnostic for every unknown reference.
+type UnknownReferenceError struct {
+	Diagnostics []Diagnostic
+}
+
+func (u *UnknownReferenceError) Error() string {
+	issues := make([]string, 0, len(u.Diagnostics))
+	for _, diagnostic := range u.Diagnostics {
+		issues = append(issues, diagnostic.String())
+	}
+	return fmt.Sprintf("unknown external references: %s", strings.Join(issues, "; "))
+}
+
+// ToCatalog converts a Layer 1 guidance document into an OSCAL Catalog. Groups and controls
+// are emitted in document order.
+//
+// External references to resources that are not in the document metadata are dropped, and the
+// catalog is returned along with an *UnknownReferenceError listing them with the severity set by
+// WithUnknownReferenceSeverity. The catalog is usable, so callers may report the diagnostics and
+// continue.
+func ToCatalog(guidance layer1.GuidanceDocument, opts ...Option) (oscalTypes.Catalog, error) {
+	options := applyOptions(opts)
+	uuids := options.UUIDs()
 
-func ToCatalog(guidance layer1.GuidanceDocument) (oscalTypes.Catalog, error) {
 	metadata := models.NewSampleMetadata()
 	metadata.Title = guidance.Metadata.Title
 
-	published, err := time.Parse(time.DateOnly, guidance.Metadata.PublicationDate)
-	if err != nil {
-		return oscalTypes.Catalog{}, err
-	}
-	metadata.Published = &published
-
-	lastModified, err := time.Parse(time.DateTime, guidance.Metadata.LastModified)
+	published, lastModified, err := options.metadataDates(guidance.Metadata.PublicationDate, guidance.Metadata.LastModified)
 	if err != nil {
 		return oscalTypes.Catalog{}, err
 	}
-
+	metadata.Published = published
 	metadata.LastModified = lastModified
 	metadata.Version = guidance.Metadata.Version
+	metadata.Remarks = guidance.Metadata.Description
 
-	authorRole := oscalTypes.Role{
-		ID:          "author",
-		Description: "Author of the guidance document",
-		Title:       "Author",
-	}
+	props := metadataProps(guidance.Metadata, options.vocabulary)
+	metadata.Props = utils.NilIfEmpty(&props)
 
-	author := oscalTypes.Party{
-		UUID: uuid.NewUUID(),
-		Type: "person",
-		Name: guidance.Metadata.Author,
-	}
-
-	responsibleParty := oscalTypes.ResponsibleParty{
-		PartyUuids: []string{author.UUID},
-		RoleId:     authorRole.ID,
-	}
-
-	metadata.Parties = &[]oscalTypes.Party{author}
-	metadata.Roles = &[]oscalTypes.Role{authorRole}
-	metadata.ResponsibleParties = &[]oscalTypes.ResponsibleParty{responsibleParty}
+	parties, roles, responsibleParties := metadataParties(guidance.Metadata, options)
+	metadata.Parties = utils.NilIfEmpty(&parties)
+	metadata.Roles = utils.NilIfEmpty(&roles)
+	metadata.ResponsibleParties = utils.NilIfEmpty(&responsibleParties)
 
 	// Create a resource map for control linking
 	resourcesMap := make(map[string]string)
-	backmatter := resourcesToBackMatter(guidance.Metadata.Resources)
+	backmatter, err := resourcesToBackMatter(guidance.Metadata.Id, guidance.Metadata.Resources, options)
+	if err != nil {
+		return oscalTypes.Catalog{}, err
+	}
 	if backmatter != nil {
 		for _, resource := range *backmatter.Resources {
 			// Extract the id from the props
@@ -67,94 +72,45 @@ func ToCatalog(guidance layer1.GuidanceDocument) (oscalTypes.Catalog, error) {
 		}
 	}
 
-	var groups []oscalTypes.Group
+	var guidelineIds []string
+	var unknownReferences []Diagnostic
 	for _, category := range guidance.Categories {
-		groups = append(groups, createControlGroup(category, resourcesMap))
-	}
-
-	catalog := oscalTypes.Catalog{
-		UUID:       uuid.NewUUID(),
-		Metadata:   metadata,
-		Groups:     utils.NilIfEmpty(&groups),
-		BackMatter: backmatter,
-	}
-	return catalog, nil
-}
-
-func createControlGroup(category layer1.Category, resourcesMap map[string]string) oscalTypes.Group {
-	group := oscalTypes.Group{
-		ID:    category.Id,
-		Title: category.Title,
-	}
-
-	controlMap := make(map[string]oscalTypes.Control)
-	for _, guideline := range category.Guidelines {
-		control, parent := guidelineToControl(guideline, resourcesMap)
-
-		if parent == "" {
-			controlMap[control.ID] = control
-		} else {
-			parentControl := controlMap[parent]
-			if parentControl.Controls == nil {
-				parentControl.Controls = &[]oscalTypes.Control{}
+		for _, guideline := range category.Guidelines {
+			guidelineIds = append(guidelineIds, guideline.Id)
+			for _, external := range guideline.ExternalReferences {
+				if _, found := resourcesMap[external]; !found {
+					unknownReferences = append(unknownReferences, Diagnostic{
+						Severity: options.unknownReferenceSeverity,
+						Location: options.normalizer.Normalize(guideline.Id),
+						Message:  fmt.Sprintf("external reference %q is not a document resource and was dropped", external),
+					})
+				}
 			}
-			*parentControl.Controls = append(*parentControl.Controls, control)
-			controlMap[parent] = parentControl
 		}
 	}
-
-	controls := make([]oscalTypes.Control, 0, len(controlMap))
-	for _, control := range controlMap {
-		controls = append(controls, control)
+	if err := normalize.Check(guidance.Metadata.Id, options.normalizer, guidelineIds); err != nil {
+		return oscalTypes.Catalog{}, err
 	}
 
-	group.Controls = utils.NilIfEmpty(&controls)
-	return group
-}
-
-func resourcesToBackMatter(resourceRefs []layer1.ResourceReference) *oscalTypes.BackMatter {
-	var resources []oscalTypes.Resource
-	for _, ref := range resourceRefs {
-		resource := oscalTypes.Resource{
-			UUID:        uuid.NewUUID(),
-			Title:       ref.Title,
-			Description: ref.Description,
-			Props: &[]oscalTypes.Property{
-				{
-					Name:  "id",
-					Value: ref.Id,
-					Ns:    extensions.TrestleNameSpace,
-				},
-			},
-			Rlinks: &[]oscalTypes.ResourceLink{
-				{
-					Href: ref.Url,
-				},
-			},
-			Citation: &oscalTypes.Citation{
-				Text: fmt.Sprintf(
-					"%s. (%s). *%s*. %s",
-					ref.IssuingBody,
-					ref.PublicationDate,
-					ref.Title,
-					ref.Url),
-			},
-		}
-		resources = append(resources, resource)
+	groups, err := createControlGroups(guidance.Categories, resourcesMap, options.normalizer)
+	if err != nil {
+		return oscalTypes.Catalog{}, err
 	}
 
-	if len(resources) == 0 {
-		return nil
+	catalog := oscalTypes.Catalog{
+		UUID:       uuids.Generate(guidance.Metadata.Id, guidance.Metadata.Version),
+		Metadata:   metadata,
+		Groups:     utils.NilIfEmpty(&groups),
+		BackMatter: backmatter,
 	}
-
-	backmatter := oscalTypes.BackMatter{
-		Resources: &resources,
+	if len(unknownReferences) > 0 {
+		return catalog, &UnknownReferenceError{Diagnostics: unknownReferences}
 	}
-	return &backmatter
+	return catalog, nil
 }
 
-func guidelineToControl(guideline layer1.Guideline, resourcesMap map[string]string) (oscalTypes.Control, string) {
-	controlId := utils.NormalizeControl(guideline.Id)
+func guidelineToControl(guideline layer1.Guideline, resourcesMap map[string]string, normalizer normalize.Normalizer) (oscalTypes.Control, string) {
+	controlId := normalizer.Normalize(guideline.Id)
 
 	control := oscalTypes.Control{
 		ID:    controlId,
@@ -164,7 +120,7 @@ func guidelineToControl(guideline layer1.Guideline, resourcesMap map[string]stri
 	var links []oscalTypes.Link
 	for _, also := range guideline.SeeAlso {
 		relatedLink := oscalTypes.Link{
-			Href: fmt.Sprintf("#%s", also),
+			Href: fmt.Sprintf("#%s", normalizer.Normalize(also)),
 			Rel:  "related",
 		}
 		links = append(links, relatedLink)
@@ -181,6 +137,7 @@ func guidelineToControl(guideline layer1.Guideline, resourcesMap map[string]stri
 		}
 		links = append(links, externalLink)
 	}
+	control.Links = utils.NilIfEmpty(&links)
 
 	// Top-level statements are required for controls
 	smtPart := oscalTypes.Part{
@@ -231,5 +188,9 @@ func guidelineToControl(guideline layer1.Guideline, resourcesMap map[string]stri
 		*control.Parts = append(*control.Parts, gdnPart)
 	}
 
-	return control, utils.NormalizeControl(guideline.BaseGuidelineID)
+	var parent string
+	if guideline.BaseGuidelineID != "" {
+		parent = normalizer.Normalize(guideline.BaseGuidelineID)
+	}
+	return control, parent
 }
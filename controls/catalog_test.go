@@ -3,12 +3,15 @@ package controls
 import (
 	"os"
 	"testing"
+	"time"
 
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/goccy/go-yaml"
 	"github.com/oscal-compass/oscal-sdk-go/validation"
 	"github.com/ossf/gemara/layer1"
 	"github.com/stretchr/testify/require"
+
+	"github.com/jpower432/gemara2oscal/normalize"
 )
 
 func TestToCatalog(t *testing.T) {
@@ -31,3 +34,82 @@ func TestToCatalog(t *testing.T) {
 	err = validator.Validate(oscalModels)
 	require.NoError(t, err)
 }
+
+func TestToCatalog_Deterministic(t *testing.T) {
+	file, err := os.Open("./testdata/800-161.yml")
+	require.NoError(t, err)
+
+	var guidance layer1.GuidanceDocument
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&guidance)
+	require.NoError(t, err)
+
+	first, err := ToCatalog(guidance, WithDeterministicUUIDs())
+	require.NoError(t, err)
+	second, err := ToCatalog(guidance, WithDeterministicUUIDs())
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+
+	random, err := ToCatalog(guidance)
+	require.NoError(t, err)
+	require.NotEqual(t, first.UUID, random.UUID)
+
+	// Missing dates are fixed as well
+	guidance.Metadata.PublicationDate = ""
+	guidance.Metadata.LastModified = ""
+	undated, err := ToCatalog(guidance, WithDeterministicUUIDs())
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(0, 0).UTC(), undated.Metadata.LastModified)
+	timestamp := time.Date(2025, 7, 26, 15, 0, 0, 0, time.UTC)
+	undated, err = ToCatalog(guidance, WithDeterministicUUIDs(), WithTimestamp(timestamp))
+	require.NoError(t, err)
+	require.Equal(t, timestamp, undated.Metadata.LastModified)
+}
+
+func TestToCatalog_Normalizer(t *testing.T) {
+	file, err := os.Open("./testdata/800-161.yml")
+	require.NoError(t, err)
+
+	var guidance layer1.GuidanceDocument
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&guidance)
+	require.NoError(t, err)
+
+	catalog, err := ToCatalog(guidance, WithNormalizer(normalize.Passthrough))
+	require.NoError(t, err)
+	require.Equal(t, "AC-5", (*(*catalog.Groups)[0].Controls)[0].ID)
+
+	guidance.Categories[0].Guidelines = append(guidance.Categories[0].Guidelines, layer1.Guideline{
+		Id:    "ac-5",
+		Title: "Separation of Duties (lowercase duplicate)",
+	})
+	_, err = ToCatalog(guidance)
+	var collisionErr *normalize.CollisionError
+	require.ErrorAs(t, err, &collisionErr)
+	require.Equal(t, []normalize.Collision{{OSCALId: "ac-5", SourceIds: []string{"AC-5", "ac-5"}}}, collisionErr.Collisions)
+}
+
+func TestToCatalog_Links(t *testing.T) {
+	file, err := os.Open("./testdata/800-161.yml")
+	require.NoError(t, err)
+
+	var guidance layer1.GuidanceDocument
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&guidance)
+	require.NoError(t, err)
+
+	catalog, err := ToCatalog(guidance)
+	require.NoError(t, err)
+	resource := (*catalog.BackMatter.Resources)[0]
+
+	groups := *catalog.Groups
+	ac5 := (*groups[0].Controls)[0]
+	require.Equal(t, []oscalTypes.Link{{Href: "#" + resource.UUID, Rel: "reference"}}, *ac5.Links)
+
+	sa15 := (*groups[3].Controls)[0]
+	require.Equal(t, []oscalTypes.Link{
+		{Href: "#sa-15.1", Rel: "related"},
+		{Href: "#sa-15.2", Rel: "related"},
+		{Href: "#sa-15.5", Rel: "related"},
+	}, (*sa15.Links)[:3])
+}
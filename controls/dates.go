@@ -0,0 +1,68 @@
+package controls
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+)
+
+// DateError is returned when a metadata date does not match any accepted layout.
+type DateError struct {
+	// Field is the name of the metadata field (e.g. publication-date).
+	Field   string
+	Value   string
+	Layouts []string
+}
+
+func (d *DateError) Error() string {
+	return fmt.Sprintf("invalid %s %q: expected one of %s", d.Field, d.Value, strings.Join(d.Layouts, ", "))
+}
+
+// parseDate parses a metadata date with the configured layouts followed by the default layouts.
+// Dates without a time zone are interpreted as UTC. The second return value is false when the
+// value is empty.
+func (o options) parseDate(field, value string) (time.Time, bool, error) {
+	if strings.TrimSpace(value) == "" {
+		return time.Time{}, false, nil
+	}
+	layouts := append(append([]string{}, o.dateLayouts...), utils.DateLayouts...)
+	if parsed, found := utils.ParseDate(value, layouts); found {
+		return parsed, true, nil
+	}
+	return time.Time{}, false, &DateError{Field: field, Value: value, Layouts: layouts}
+}
+
+// metadataDates returns the publication and last modified dates of a document. Missing values
+// fall back to the configured defaults. When no last modified date is available, the publication
+// date is used, and the current time (a fixed timestamp in deterministic mode) when neither is set.
+func (o options) metadataDates(publicationDate, lastModified string) (*time.Time, time.Time, error) {
+	published, found, err := o.parseDate("publication-date", publicationDate)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var publishedPtr *time.Time
+	switch {
+	case found:
+		publishedPtr = &published
+	case o.defaultPublished != nil:
+		defaultPublished := *o.defaultPublished
+		publishedPtr = &defaultPublished
+	}
+
+	modified, found, err := o.parseDate("last-modified", lastModified)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	switch {
+	case found:
+	case o.defaultLastModified != nil:
+		modified = *o.defaultLastModified
+	case publishedPtr != nil:
+		modified = *publishedPtr
+	default:
+		modified = o.Now()
+	}
+	return publishedPtr, modified, nil
+}
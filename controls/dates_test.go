@@ -0,0 +1,73 @@
+package controls
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ossf/gemara/layer1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		layouts []string
+		value   string
+		want    time.Time
+		found   bool
+		wantErr string
+	}{
+		{name: "RFC3339", value: "2025-07-26T15:00:00-04:00", want: time.Date(2025, 7, 26, 19, 0, 0, 0, time.UTC), found: true},
+		{name: "Date time", value: "2025-07-26 15:00:00", want: time.Date(2025, 7, 26, 15, 0, 0, 0, time.UTC), found: true},
+		{name: "Date", value: "2025-07-26", want: time.Date(2025, 7, 26, 0, 0, 0, 0, time.UTC), found: true},
+		{name: "Month", value: "2022-05", want: time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC), found: true},
+		{name: "Year", value: "2022", want: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), found: true},
+		{name: "Custom layout", layouts: []string{"January 2, 2006"}, value: "July 26, 2025", want: time.Date(2025, 7, 26, 0, 0, 0, 0, time.UTC), found: true},
+		{name: "Empty", value: " "},
+		{name: "Invalid", value: "26/07/2025", wantErr: `invalid publication-date "26/07/2025"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := applyOptions([]Option{WithDateLayouts(tt.layouts...)})
+			got, found, err := options.parseDate("publication-date", tt.value)
+			if tt.wantErr != "" {
+				var dateErr *DateError
+				require.ErrorAs(t, err, &dateErr)
+				require.Equal(t, "publication-date", dateErr.Field)
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.found, found)
+			require.True(t, tt.want.Equal(got), "got %s", got)
+		})
+	}
+}
+
+func TestToCatalog_Dates(t *testing.T) {
+	guidance := layer1.GuidanceDocument{
+		Metadata: layer1.Metadata{
+			Id:              "dates",
+			Title:           "Dates",
+			PublicationDate: "2022-05",
+		},
+	}
+
+	catalog, err := ToCatalog(guidance)
+	require.NoError(t, err)
+	require.True(t, time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC).Equal(*catalog.Metadata.Published))
+	require.True(t, catalog.Metadata.Published.Equal(catalog.Metadata.LastModified))
+
+	lastModified := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	guidance.Metadata.PublicationDate = ""
+	catalog, err = ToCatalog(guidance, WithDefaultLastModified(lastModified))
+	require.NoError(t, err)
+	require.Nil(t, catalog.Metadata.Published)
+	require.True(t, lastModified.Equal(catalog.Metadata.LastModified))
+
+	guidance.Metadata.LastModified = "yesterday"
+	_, err = ToCatalog(guidance)
+	require.EqualError(t, err, `invalid last-modified "yesterday": expected one of `+
+		"2006-01-02T15:04:05.999999999Z07:00, 2006-01-02T15:04:05Z07:00, 2006-01-02 15:04:05, 2006-01-02T15:04:05, "+
+		"2006-01-02 15:04, 2006-01-02T15:04, 2006-01-02, 2006/01/02, 2006-01, 2006")
+}
@@ -0,0 +1,25 @@
+package controls
+
+import "fmt"
+
+// Severity is the severity of a Diagnostic.
+type Severity string
+
+const (
+	// SeverityWarning indicates content was changed or dropped, but the result is still usable.
+	SeverityWarning Severity = "warning"
+	// SeverityError indicates the result is incomplete or invalid.
+	SeverityError Severity = "error"
+)
+
+// Diagnostic describes an issue found while converting or validating a catalog.
+type Diagnostic struct {
+	Severity Severity
+	// Location is the id of the element the diagnostic applies to (e.g. a control or part id).
+	Location string
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s", d.Severity, d.Location, d.Message)
+}
@@ -0,0 +1,392 @@
+package controls
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/ossf/gemara/layer1"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+	"github.com/jpower432/gemara2oscal/vocabulary"
+)
+
+// oscalNameSpace is the namespace of the props defined by OSCAL itself. Props without a namespace
+// are in this namespace.
+const oscalNameSpace = "http://csrc.nist.gov/ns/oscal"
+
+// FromCatalog converts an OSCAL Catalog into a Layer 1 guidance document. Groups become
+// categories, controls and control enhancements become guidelines (enhancements reference
+// their parent through the base guideline id), statement items become guideline parts, the
+// assessment objective becomes the guideline objective, guidance becomes recommendations and
+// back-matter resources become metadata resources.
+//
+// Metadata and resource props are read in the namespace of the vocabulary set with WithVocabulary.
+// Catalog content that has no Layer 1 representation (e.g. params, unknown parts or props in other
+// namespaces) is dropped and reported as warning diagnostics. Standard OSCAL label props are
+// derived from ids and dropped without a diagnostic.
+func FromCatalog(catalog oscalTypes.Catalog, opts ...Option) (layer1.GuidanceDocument, []Diagnostic) {
+	options := applyOptions(opts)
+	converter := reverseConverter{
+		vocabulary: options.vocabulary,
+		resources:  make(map[string]string),
+	}
+
+	guidance := layer1.GuidanceDocument{
+		Metadata: converter.metadata(catalog),
+	}
+
+	if catalog.Params != nil && len(*catalog.Params) > 0 {
+		converter.warn(catalog.UUID, "catalog params are not represented in Layer 1")
+	}
+
+	if catalog.Groups != nil {
+		for _, group := range *catalog.Groups {
+			guidance.Categories = append(guidance.Categories, converter.groupToCategories(group)...)
+		}
+	}
+
+	if catalog.Controls != nil && len(*catalog.Controls) > 0 {
+		converter.warn(catalog.UUID, "ungrouped controls were added to the \"ungrouped\" category")
+		category := layer1.Category{
+			Id:    "ungrouped",
+			Title: "Ungrouped",
+		}
+		for _, control := range *catalog.Controls {
+			category.Guidelines = append(category.Guidelines, converter.controlToGuidelines(control, "")...)
+		}
+		guidance.Categories = append(guidance.Categories, category)
+	}
+
+	return guidance, converter.diagnostics
+}
+
+type reverseConverter struct {
+	vocabulary vocabulary.Vocabulary
+	// resources maps back-matter resource UUIDs to Layer 1 resource ids
+	resources   map[string]string
+	diagnostics []Diagnostic
+}
+
+func (r *reverseConverter) warn(location, format string, args ...any) {
+	r.diagnostics = append(r.diagnostics, Diagnostic{
+		Severity: SeverityWarning,
+		Location: location,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (r *reverseConverter) metadata(catalog oscalTypes.Catalog) layer1.Metadata {
+	oscalMetadata := catalog.Metadata
+	metadata := layer1.Metadata{
+		Id:           catalog.UUID,
+		Title:        oscalMetadata.Title,
+		Description:  oscalMetadata.Remarks,
+		Version:      oscalMetadata.Version,
+		LastModified: oscalMetadata.LastModified.Format(time.DateTime),
+	}
+
+	if oscalMetadata.DocumentIds != nil && len(*oscalMetadata.DocumentIds) > 0 {
+		metadata.Id = (*oscalMetadata.DocumentIds)[0].Identifier
+	}
+
+	if oscalMetadata.Published != nil {
+		metadata.PublicationDate = oscalMetadata.Published.Format(time.DateOnly)
+	}
+
+	metadata.Author = authorNames(oscalMetadata)
+
+	if oscalMetadata.Props != nil {
+		applicability := layer1.Applicability{}
+		for _, prop := range *oscalMetadata.Props {
+			if prop.Ns != r.vocabulary.Namespace {
+				r.warn(catalog.UUID, "metadata prop %q in namespace %q is not represented in Layer 1", prop.Name, prop.Ns)
+				continue
+			}
+			switch prop.Name {
+			case "document-type":
+				metadata.DocumentType = layer1.DocumentType(prop.Value)
+			case "jurisdiction":
+				applicability.Jurisdictions = append(applicability.Jurisdictions, prop.Value)
+			case "technology-domain":
+				applicability.TechnologyDomains = append(applicability.TechnologyDomains, prop.Value)
+			case "industry-sector":
+				applicability.IndustrySectors = append(applicability.IndustrySectors, prop.Value)
+			case "exemption":
+				metadata.Exemptions = append(metadata.Exemptions, prop.Value)
+			default:
+				r.warn(catalog.UUID, "metadata prop %q is not represented in Layer 1", prop.Name)
+			}
+		}
+		if applicability.Jurisdictions != nil || applicability.TechnologyDomains != nil || applicability.IndustrySectors != nil {
+			metadata.Applicabilty = &applicability
+		}
+	}
+
+	if catalog.BackMatter != nil && catalog.BackMatter.Resources != nil {
+		for _, resource := range *catalog.BackMatter.Resources {
+			ref := r.resourceToReference(resource)
+			r.resources[resource.UUID] = ref.Id
+			metadata.Resources = append(metadata.Resources, ref)
+		}
+	}
+	return metadata
+}
+
+// authorNames returns the names of all parties with the author role, separated as read by
+// splitAuthors.
+func authorNames(metadata oscalTypes.Metadata) string {
+	if metadata.ResponsibleParties == nil || metadata.Parties == nil {
+		return ""
+	}
+
+	partyNames := make(map[string]string, len(*metadata.Parties))
+	for _, party := range *metadata.Parties {
+		partyNames[party.UUID] = party.Name
+	}
+
+	var authors []string
+	for _, responsible := range *metadata.ResponsibleParties {
+		if responsible.RoleId != RoleAuthor {
+			continue
+		}
+		for _, partyUUID := range responsible.PartyUuids {
+			if name := partyNames[partyUUID]; name != "" {
+				authors = append(authors, name)
+			}
+		}
+	}
+	return strings.Join(authors, authorSeparator+" ")
+}
+
+func (r *reverseConverter) resourceToReference(resource oscalTypes.Resource) layer1.ResourceReference {
+	ref := layer1.ResourceReference{
+		Id:          resource.UUID,
+		Title:       resource.Title,
+		Description: resource.Description,
+	}
+
+	if resource.Props != nil {
+		for _, prop := range *resource.Props {
+			if prop.Ns != r.vocabulary.Namespace {
+				r.warn(resource.UUID, "resource prop %q in namespace %q is not represented in Layer 1", prop.Name, prop.Ns)
+				continue
+			}
+			switch prop.Name {
+			case "id":
+				ref.Id = prop.Value
+			case "issuing-body":
+				ref.IssuingBody = prop.Value
+			case "publication-date":
+				ref.PublicationDate = prop.Value
+			default:
+				r.warn(resource.UUID, "resource prop %q is not represented in Layer 1", prop.Name)
+			}
+		}
+	}
+
+	if resource.Rlinks != nil && len(*resource.Rlinks) > 0 {
+		ref.Url = (*resource.Rlinks)[0].Href
+		if len(*resource.Rlinks) > 1 {
+			r.warn(resource.UUID, "only the first rlink is represented in Layer 1")
+		}
+	}
+
+	if resource.Citation != nil {
+		r.warn(resource.UUID, "resource citation is not represented in Layer 1")
+	}
+
+	if resource.Base64 != nil {
+		r.warn(resource.UUID, "embedded base64 content is not represented in Layer 1")
+	}
+	return ref
+}
+
+func (r *reverseConverter) groupToCategories(group oscalTypes.Group) []layer1.Category {
+	category := layer1.Category{
+		Id:    group.ID,
+		Title: group.Title,
+	}
+	if category.Id == "" {
+		category.Id = utils.ToToken(group.Title)
+		r.warn(category.Id, "group has no id, derived %q from the title", category.Id)
+	}
+
+	if group.Parts != nil {
+		for _, part := range *group.Parts {
+			if part.Name == "overview" {
+				category.Description = part.Prose
+				continue
+			}
+			r.warn(category.Id, "group part %q is not represented in Layer 1", part.Name)
+		}
+	}
+
+	if group.Params != nil && len(*group.Params) > 0 {
+		r.warn(category.Id, "group params are not represented in Layer 1")
+	}
+
+	if group.Controls != nil {
+		for _, control := range *group.Controls {
+			category.Guidelines = append(category.Guidelines, r.controlToGuidelines(control, "")...)
+		}
+	}
+
+	categories := []layer1.Category{category}
+
+	// Layer 1 categories cannot be nested, so sub-groups are flattened
+	if group.Groups != nil {
+		for _, subGroup := range *group.Groups {
+			r.warn(category.Id, "sub-group %q was flattened into a top-level category", subGroup.ID)
+			categories = append(categories, r.groupToCategories(subGroup)...)
+		}
+	}
+	return categories
+}
+
+// controlToGuidelines returns the guideline for the control followed by the guidelines for
+// all of its enhancements.
+func (r *reverseConverter) controlToGuidelines(control oscalTypes.Control, baseId string) []layer1.Guideline {
+	guideline := layer1.Guideline{
+		Id:              control.ID,
+		Title:           control.Title,
+		BaseGuidelineID: baseId,
+	}
+
+	if control.Parts != nil {
+		for _, part := range *control.Parts {
+			switch part.Name {
+			case "statement":
+				guideline.GuidelineParts = append(guideline.GuidelineParts, r.statementToParts(control.ID, part)...)
+			case "assessment-objective", "objective":
+				guideline.Objective = joinProse(part)
+			case "guidance":
+				guideline.Recommendations = append(guideline.Recommendations, joinProse(part))
+			default:
+				r.warn(control.ID, "part %q is not represented in Layer 1", part.Name)
+			}
+		}
+	}
+
+	if control.Links != nil {
+		for _, link := range *control.Links {
+			target, isFragment := strings.CutPrefix(link.Href, "#")
+			switch {
+			case isFragment && link.Rel == "related":
+				guideline.SeeAlso = append(guideline.SeeAlso, target)
+			case isFragment && link.Rel == "reference":
+				resourceId, found := r.resources[target]
+				if !found {
+					r.warn(control.ID, "reference link %q does not resolve to a back-matter resource", link.Href)
+					continue
+				}
+				guideline.ExternalReferences = append(guideline.ExternalReferences, resourceId)
+			default:
+				r.warn(control.ID, "link %q with rel %q is not represented in Layer 1", link.Href, link.Rel)
+			}
+		}
+	}
+
+	if control.Params != nil && len(*control.Params) > 0 {
+		r.warn(control.ID, "%d params are not represented in Layer 1", len(*control.Params))
+	}
+
+	if names := unrepresentedProps(control.Props); len(names) > 0 {
+		r.warn(control.ID, "props are not represented in Layer 1: %s", strings.Join(names, ", "))
+	}
+
+	guidelines := []layer1.Guideline{guideline}
+	if control.Controls != nil {
+		for _, enhancement := range *control.Controls {
+			guidelines = append(guidelines, r.controlToGuidelines(enhancement, control.ID)...)
+		}
+	}
+	return guidelines
+}
+
+// statementToParts flattens statement items into guideline parts. Guidance sub-parts
+// of an item become the part recommendations.
+func (r *reverseConverter) statementToParts(controlId string, statement oscalTypes.Part) []layer1.Part {
+	var parts []layer1.Part
+	if statement.Prose != "" {
+		parts = append(parts, layer1.Part{
+			Id:    "smt",
+			Prose: statement.Prose,
+		})
+	}
+
+	if statement.Parts == nil {
+		return parts
+	}
+
+	prefix := fmt.Sprintf("%s_smt.", controlId)
+	for _, item := range *statement.Parts {
+		if item.Name == "guidance" {
+			r.warn(controlId, "statement guidance %q is not represented in Layer 1", item.ID)
+			continue
+		}
+
+		part := layer1.Part{
+			Id:    strings.TrimPrefix(item.ID, prefix),
+			Title: item.Title,
+			Prose: item.Prose,
+		}
+		if names := unrepresentedProps(item.Props); len(names) > 0 {
+			r.warn(controlId, "props of statement item %q are not represented in Layer 1: %s", item.ID, strings.Join(names, ", "))
+		}
+
+		var nested []oscalTypes.Part
+		if item.Parts != nil {
+			for _, subPart := range *item.Parts {
+				if subPart.Name == "guidance" {
+					part.Recommendations = append(part.Recommendations, joinProse(subPart))
+					continue
+				}
+				nested = append(nested, subPart)
+			}
+		}
+		parts = append(parts, part)
+
+		// Nested items are flattened since Layer 1 parts cannot be nested
+		if len(nested) > 0 {
+			nestedItem := oscalTypes.Part{Name: "statement", Parts: &nested}
+			parts = append(parts, r.statementToParts(controlId, nestedItem)...)
+		}
+	}
+	return parts
+}
+
+// unrepresentedProps returns the sorted names of the props other than the standard OSCAL label,
+// which is derived from the control or part id.
+func unrepresentedProps(props *[]oscalTypes.Property) []string {
+	if props == nil {
+		return nil
+	}
+	var names []string
+	for _, prop := range *props {
+		if prop.Name == "label" && (prop.Ns == "" || prop.Ns == oscalNameSpace) {
+			continue
+		}
+		names = append(names, prop.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// joinProse returns the prose of the part and all of its sub-parts separated by newlines.
+func joinProse(part oscalTypes.Part) string {
+	var prose []string
+	if part.Prose != "" {
+		prose = append(prose, part.Prose)
+	}
+	if part.Parts != nil {
+		for _, subPart := range *part.Parts {
+			if subProse := joinProse(subPart); subProse != "" {
+				prose = append(prose, subProse)
+			}
+		}
+	}
+	return strings.Join(prose, "\n")
+}
@@ -0,0 +1,124 @@
+package controls
+
+import (
+	"os"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/ossf/gemara/layer1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromCatalog(t *testing.T) {
+	file, err := os.Open("./testdata/800-161.yml")
+	require.NoError(t, err)
+
+	var guidance layer1.GuidanceDocument
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&guidance)
+	require.NoError(t, err)
+
+	catalog, err := ToCatalog(guidance)
+	require.NoError(t, err)
+
+	got, diagnostics := FromCatalog(catalog)
+	require.Empty(t, diagnostics)
+
+	require.Equal(t, guidance.Metadata.Title, got.Metadata.Title)
+	require.Equal(t, guidance.Metadata.PublicationDate, got.Metadata.PublicationDate)
+	require.Equal(t, guidance.Metadata.LastModified, got.Metadata.LastModified)
+	require.Equal(t, guidance.Metadata.Author, got.Metadata.Author)
+	require.Len(t, got.Metadata.Resources, 1)
+	require.Equal(t, "nist-sp-800-161r1", got.Metadata.Resources[0].Id)
+
+	require.Len(t, got.Categories, 5)
+	au := got.Categories[1]
+	require.Len(t, au.Guidelines, 2)
+	require.Equal(t, "au-6", au.Guidelines[0].Id)
+	require.Equal(t, []string{"nist-sp-800-161r1"}, au.Guidelines[0].ExternalReferences)
+	require.Equal(t, "au-6.9", au.Guidelines[1].Id)
+	require.Equal(t, "au-6", au.Guidelines[1].BaseGuidelineID)
+
+	sa := got.Categories[3].Guidelines[0]
+	require.Len(t, sa.SeeAlso, 5)
+	require.Equal(t, guidance.Categories[3].Guidelines[0].Objective, sa.Objective)
+}
+
+func TestFromCatalog_Diagnostics(t *testing.T) {
+	catalog := oscalTypes.Catalog{
+		UUID: "c2a4e1f6-8d1a-4a7c-9c4e-0b6e3f4b6a11",
+		BackMatter: &oscalTypes.BackMatter{
+			Resources: &[]oscalTypes.Resource{
+				{
+					UUID:     "5a1f3e2b-9c7d-4e6a-8b0f-1d2c3e4f5a6b",
+					Title:    "NIST SP 800-53",
+					Props:    &[]oscalTypes.Property{{Name: "id", Value: "sp-800-53", Ns: "https://example.com/ns"}},
+					Citation: &oscalTypes.Citation{Text: "NIST SP 800-53 Rev. 5"},
+				},
+			},
+		},
+		Groups: &[]oscalTypes.Group{
+			{
+				ID:    "ac",
+				Title: "Access Control",
+				Groups: &[]oscalTypes.Group{
+					{ID: "ac-sub", Title: "Sub-group"},
+				},
+				Controls: &[]oscalTypes.Control{
+					{
+						ID:     "ac-1",
+						Title:  "Policy and Procedures",
+						Params: &[]oscalTypes.Parameter{{ID: "ac-01_odp.01"}},
+						Parts: &[]oscalTypes.Part{
+							{
+								Name:  "statement",
+								ID:    "ac-1_smt",
+								Prose: "The organization:",
+								Parts: &[]oscalTypes.Part{
+									{
+										Name:  "item",
+										ID:    "ac-1_smt.a",
+										Prose: "Develops policy;",
+										Props: &[]oscalTypes.Property{
+											{Name: "label", Value: "a."},
+											{Name: "sort-id", Value: "ac-01.a"},
+										},
+										Parts: &[]oscalTypes.Part{
+											{Name: "item", ID: "ac-1_smt.a.1", Prose: "Addresses purpose;"},
+										},
+									},
+								},
+							},
+							{Name: "assessment-method", ID: "ac-1_mth"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got, diagnostics := FromCatalog(catalog)
+	require.Len(t, got.Categories, 2)
+	parts := got.Categories[0].Guidelines[0].GuidelineParts
+	require.Equal(t, []layer1.Part{
+		{Id: "smt", Prose: "The organization:"},
+		{Id: "a", Prose: "Develops policy;"},
+		{Id: "a.1", Prose: "Addresses purpose;"},
+	}, parts)
+
+	// The id prop is in a foreign namespace, so the resource keeps its UUID as id
+	require.Equal(t, "5a1f3e2b-9c7d-4e6a-8b0f-1d2c3e4f5a6b", got.Metadata.Resources[0].Id)
+
+	require.Len(t, diagnostics, 6)
+	for _, diagnostic := range diagnostics {
+		require.Equal(t, SeverityWarning, diagnostic.Severity)
+	}
+	require.Equal(t, `resource prop "id" in namespace "https://example.com/ns" is not represented in Layer 1`, diagnostics[0].Message)
+	require.Equal(t, "resource citation is not represented in Layer 1", diagnostics[1].Message)
+	require.Contains(t, diagnostics, Diagnostic{
+		Severity: SeverityWarning,
+		Location: "ac-1",
+		Message:  `props of statement item "ac-1_smt.a" are not represented in Layer 1: sort-id`,
+	})
+}
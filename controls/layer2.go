@@ -0,0 +1,196 @@
+package controls
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/models"
+	"github.com/ossf/gemara/layer2"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+	"github.com/jpower432/gemara2oscal/normalize"
+)
+
+// Layer2ToCatalog converts a Layer 2 control catalog into an OSCAL Catalog. Control families
+// become groups, controls become OSCAL controls with the control objective as the assessment-objective,
+// assessment requirements become statement items, and recommended parameters become control params
+// referenced from the statement item prose with insert placeholders.
+//
+// Group ids are derived from the family titles and suffixed with a number when titles repeat.
+// Params are unique within the catalog, so a parameter shared by several assessment requirements
+// becomes a single param, and an error is returned when its definitions differ.
+func Layer2ToCatalog(catalog layer2.Catalog, opts ...Option) (oscalTypes.Catalog, error) {
+	options := applyOptions(opts)
+	uuids := options.UUIDs()
+
+	metadata := models.NewSampleMetadata()
+	metadata.Title = catalog.Metadata.Title
+	if catalog.Metadata.Version != "" {
+		metadata.Version = catalog.Metadata.Version
+	}
+
+	_, lastModified, err := options.metadataDates("", catalog.Metadata.LastModified)
+	if err != nil {
+		return oscalTypes.Catalog{}, err
+	}
+	metadata.LastModified = lastModified
+
+	var controlIds []string
+	for _, family := range catalog.ControlFamilies {
+		for _, control := range family.Controls {
+			controlIds = append(controlIds, control.Id)
+		}
+	}
+	if err := normalize.Check(catalog.Metadata.Id, options.normalizer, controlIds); err != nil {
+		return oscalTypes.Catalog{}, err
+	}
+
+	converter := layer2Converter{
+		normalizer: options.normalizer,
+		groupIds:   make(map[string]bool),
+		params:     make(map[string]oscalTypes.Parameter),
+	}
+	var groups []oscalTypes.Group
+	for _, family := range catalog.ControlFamilies {
+		group, err := converter.familyToGroup(family)
+		if err != nil {
+			return oscalTypes.Catalog{}, err
+		}
+		groups = append(groups, group)
+	}
+
+	oscalCatalog := oscalTypes.Catalog{
+		UUID:     uuids.Generate(catalog.Metadata.Id, catalog.Metadata.Version),
+		Metadata: metadata,
+		Groups:   utils.NilIfEmpty(&groups),
+	}
+	return oscalCatalog, nil
+}
+
+type layer2Converter struct {
+	normalizer normalize.Normalizer
+	groupIds   map[string]bool
+	// params holds the params defined so far by id
+	params map[string]oscalTypes.Parameter
+}
+
+func (l *layer2Converter) familyToGroup(family layer2.ControlFamily) (oscalTypes.Group, error) {
+	// Control families do not have an id, so one is derived from the title
+	groupId := utils.ToToken(family.Title)
+	for i := 2; l.groupIds[groupId]; i++ {
+		groupId = fmt.Sprintf("%s-%d", utils.ToToken(family.Title), i)
+	}
+	l.groupIds[groupId] = true
+
+	group := oscalTypes.Group{
+		ID:    groupId,
+		Title: family.Title,
+	}
+
+	if family.Description != "" {
+		group.Parts = &[]oscalTypes.Part{
+			{
+				Name:  "overview",
+				ID:    fmt.Sprintf("%s_ovw", group.ID),
+				Prose: strings.TrimSpace(family.Description),
+			},
+		}
+	}
+
+	controls := make([]oscalTypes.Control, 0, len(family.Controls))
+	for _, control := range family.Controls {
+		oscalControl, err := l.layer2ControlToControl(control)
+		if err != nil {
+			return oscalTypes.Group{}, err
+		}
+		controls = append(controls, oscalControl)
+	}
+	group.Controls = utils.NilIfEmpty(&controls)
+	return group, nil
+}
+
+func (l *layer2Converter) layer2ControlToControl(layer2Control layer2.Control) (oscalTypes.Control, error) {
+	normalizer := l.normalizer
+	controlId := normalizer.Normalize(layer2Control.Id)
+
+	control := oscalTypes.Control{
+		ID:    controlId,
+		Title: singleLine(layer2Control.Title),
+	}
+
+	// Top-level statements are required for controls
+	smtPart := oscalTypes.Part{
+		Name: "statement",
+		ID:   fmt.Sprintf("%s_smt", controlId),
+	}
+
+	var params []oscalTypes.Parameter
+	var items []oscalTypes.Part
+	for _, requirement := range layer2Control.AssessmentRequirements {
+		itemId := fmt.Sprintf("%s_smt.%s", controlId, requirementSuffix(controlId, normalizer.Normalize(requirement.Id)))
+		item := oscalTypes.Part{
+			Name:  "item",
+			ID:    itemId,
+			Prose: insertParams(strings.TrimSpace(requirement.Text), requirement.RecommendedParameters),
+			Props: &[]oscalTypes.Property{
+				{
+					Name:  "label",
+					Value: requirement.Id,
+				},
+			},
+		}
+
+		if requirement.Recommendation != "" {
+			item.Parts = &[]oscalTypes.Part{
+				{
+					Name:  "guidance",
+					ID:    fmt.Sprintf("%s_gdn", itemId),
+					Prose: strings.TrimSpace(requirement.Recommendation),
+				},
+			}
+		}
+		items = append(items, item)
+
+		for _, parameter := range requirement.RecommendedParameters {
+			param := parameterToParam(parameter)
+			if existing, found := l.params[param.ID]; found {
+				if !reflect.DeepEqual(existing, param) {
+					return oscalTypes.Control{}, fmt.Errorf("assessment requirement %s redefines parameter %s", requirement.Id, param.ID)
+				}
+				continue
+			}
+			l.params[param.ID] = param
+			params = append(params, param)
+		}
+	}
+	smtPart.Parts = utils.NilIfEmpty(&items)
+	control.Parts = &[]oscalTypes.Part{smtPart}
+	control.Params = utils.NilIfEmpty(&params)
+
+	if layer2Control.Objective != "" {
+		objPart := oscalTypes.Part{
+			Name:  "assessment-objective",
+			ID:    fmt.Sprintf("%s_obj", controlId),
+			Prose: strings.TrimSpace(layer2Control.Objective),
+		}
+		*control.Parts = append(*control.Parts, objPart)
+	}
+
+	return control, nil
+}
+
+// requirementSuffix returns the normalized assessment requirement id relative to its control
+// (e.g. osps-qa-07.01 becomes 01 for control osps-qa-07).
+func requirementSuffix(controlId, normalized string) string {
+	if suffix, found := strings.CutPrefix(normalized, controlId+"."); found && suffix != "" {
+		return suffix
+	}
+	return normalized
+}
+
+// singleLine collapses multi-line YAML text into a single line for OSCAL markup-line fields.
+func singleLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
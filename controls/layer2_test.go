@@ -0,0 +1,105 @@
+package controls
+
+import (
+	"os"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/ossf/gemara/layer2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayer2ToCatalog(t *testing.T) {
+	file, err := os.Open("../component/testdata/good-osps.yml")
+	require.NoError(t, err)
+
+	var layer2Catalog layer2.Catalog
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&layer2Catalog)
+	require.NoError(t, err)
+
+	catalog, err := Layer2ToCatalog(layer2Catalog)
+	require.NoError(t, err)
+
+	require.Len(t, *catalog.Groups, 1)
+	group := (*catalog.Groups)[0]
+	require.Equal(t, "quality", group.ID)
+	require.Len(t, *group.Controls, 1)
+
+	control := (*group.Controls)[0]
+	require.Equal(t, "osps-qa-07", control.ID)
+	params := *control.Params
+	require.Len(t, params, 1)
+	require.Equal(t, "main_branch_min_approvals", params[0].ID)
+	require.Equal(t, "Minimum approvals on the default branch", params[0].Label)
+	require.Equal(t, []string{"1"}, *params[0].Values)
+
+	parts := *control.Parts
+	require.Len(t, parts, 2)
+	require.Equal(t, "assessment-objective", parts[1].Name)
+	items := *parts[0].Parts
+	require.Len(t, items, 1)
+	require.Equal(t, "osps-qa-07_smt.01", items[0].ID)
+	require.Contains(t, items[0].Prose, "{{ insert: param, main_branch_min_approvals }}")
+
+	oscalModels := oscalTypes.OscalModels{
+		Catalog: &catalog,
+	}
+
+	validator := validation.NewSchemaValidator()
+	err = validator.Validate(oscalModels)
+	require.NoError(t, err)
+}
+
+func TestLayer2ToCatalog_Duplicates(t *testing.T) {
+	approvals := layer2.Parameter{Id: "min_approvals", Description: "Minimum approvals", Default: 1}
+	catalog := layer2.Catalog{
+		Metadata: layer2.Metadata{Id: "EXAMPLE", Title: "Example"},
+		ControlFamilies: []layer2.ControlFamily{
+			{
+				Title: "Quality",
+				Controls: []layer2.Control{
+					{
+						Id:    "QA-01",
+						Title: "Code review",
+						AssessmentRequirements: []layer2.AssessmentRequirement{
+							{Id: "QA-01.01", Text: "Require reviews.", RecommendedParameters: []layer2.Parameter{approvals}},
+							{Id: "QA-01.02", Text: "Require reviews on release branches.", RecommendedParameters: []layer2.Parameter{approvals}},
+						},
+					},
+				},
+			},
+			{
+				Title: "Quality",
+				Controls: []layer2.Control{
+					{
+						Id:    "QA-02",
+						Title: "Dependency review",
+						AssessmentRequirements: []layer2.AssessmentRequirement{
+							{Id: "QA-02.01", Text: "Review dependency updates.", RecommendedParameters: []layer2.Parameter{approvals}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	oscalCatalog, err := Layer2ToCatalog(catalog)
+	require.NoError(t, err)
+	groups := *oscalCatalog.Groups
+	require.Equal(t, "quality", groups[0].ID)
+	require.Equal(t, "quality-2", groups[1].ID)
+	require.Len(t, *(*groups[0].Controls)[0].Params, 1)
+	require.Nil(t, (*groups[1].Controls)[0].Params)
+
+	err = validation.NewSchemaValidator().Validate(oscalTypes.OscalModels{Catalog: &oscalCatalog})
+	require.NoError(t, err)
+
+	catalog.ControlFamilies[1].Controls[0].AssessmentRequirements[0].RecommendedParameters = []layer2.Parameter{
+		{Id: "min_approvals", Description: "Minimum approvals", Default: 2},
+	}
+	_, err = Layer2ToCatalog(catalog)
+	require.EqualError(t, err, "assessment requirement QA-02.01 redefines parameter min_approvals")
+}
@@ -0,0 +1,176 @@
+package controls
+
+import (
+	"fmt"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+)
+
+type linkTarget int
+
+const (
+	targetControl linkTarget = iota
+	targetPart
+	targetResource
+	targetOther
+)
+
+// ValidateLinks checks that every fragment link (#id) in the catalog resolves to a control,
+// part or back-matter resource in the same catalog, and that reference links point to back-matter
+// resources. Every problem found is reported as a diagnostic with the given severity.
+func ValidateLinks(catalog oscalTypes.Catalog, severity Severity) []Diagnostic {
+	targets := make(map[string]linkTarget)
+	if catalog.BackMatter != nil && catalog.BackMatter.Resources != nil {
+		for _, resource := range *catalog.BackMatter.Resources {
+			targets[resource.UUID] = targetResource
+		}
+	}
+	if catalog.Params != nil {
+		collectParamTargets(*catalog.Params, targets)
+	}
+	if catalog.Controls != nil {
+		collectControlTargets(*catalog.Controls, targets)
+	}
+	if catalog.Groups != nil {
+		collectGroupTargets(*catalog.Groups, targets)
+	}
+
+	validator := linkValidator{
+		severity: severity,
+		targets:  targets,
+	}
+	if catalog.Controls != nil {
+		validator.controls(*catalog.Controls)
+	}
+	if catalog.Groups != nil {
+		validator.groups(*catalog.Groups)
+	}
+	return validator.diagnostics
+}
+
+func collectGroupTargets(groups []oscalTypes.Group, targets map[string]linkTarget) {
+	for _, group := range groups {
+		if group.ID != "" {
+			targets[group.ID] = targetOther
+		}
+		if group.Params != nil {
+			collectParamTargets(*group.Params, targets)
+		}
+		if group.Parts != nil {
+			collectPartTargets(*group.Parts, targets)
+		}
+		if group.Controls != nil {
+			collectControlTargets(*group.Controls, targets)
+		}
+		if group.Groups != nil {
+			collectGroupTargets(*group.Groups, targets)
+		}
+	}
+}
+
+func collectControlTargets(controls []oscalTypes.Control, targets map[string]linkTarget) {
+	for _, control := range controls {
+		targets[control.ID] = targetControl
+		if control.Params != nil {
+			collectParamTargets(*control.Params, targets)
+		}
+		if control.Parts != nil {
+			collectPartTargets(*control.Parts, targets)
+		}
+		if control.Controls != nil {
+			collectControlTargets(*control.Controls, targets)
+		}
+	}
+}
+
+func collectPartTargets(parts []oscalTypes.Part, targets map[string]linkTarget) {
+	for _, part := range parts {
+		if part.ID != "" {
+			targets[part.ID] = targetPart
+		}
+		if part.Parts != nil {
+			collectPartTargets(*part.Parts, targets)
+		}
+	}
+}
+
+func collectParamTargets(params []oscalTypes.Parameter, targets map[string]linkTarget) {
+	for _, param := range params {
+		targets[param.ID] = targetOther
+	}
+}
+
+type linkValidator struct {
+	severity    Severity
+	targets     map[string]linkTarget
+	diagnostics []Diagnostic
+}
+
+func (v *linkValidator) groups(groups []oscalTypes.Group) {
+	for _, group := range groups {
+		location := group.ID
+		if location == "" {
+			location = group.Title
+		}
+		v.links(location, group.Links)
+		if group.Parts != nil {
+			v.parts(*group.Parts)
+		}
+		if group.Controls != nil {
+			v.controls(*group.Controls)
+		}
+		if group.Groups != nil {
+			v.groups(*group.Groups)
+		}
+	}
+}
+
+func (v *linkValidator) controls(controls []oscalTypes.Control) {
+	for _, control := range controls {
+		v.links(control.ID, control.Links)
+		if control.Parts != nil {
+			v.parts(*control.Parts)
+		}
+		if control.Controls != nil {
+			v.controls(*control.Controls)
+		}
+	}
+}
+
+func (v *linkValidator) parts(parts []oscalTypes.Part) {
+	for _, part := range parts {
+		v.links(part.ID, part.Links)
+		if part.Parts != nil {
+			v.parts(*part.Parts)
+		}
+	}
+}
+
+func (v *linkValidator) links(location string, links *[]oscalTypes.Link) {
+	if links == nil {
+		return
+	}
+	for _, link := range *links {
+		fragment, isFragment := strings.CutPrefix(link.Href, "#")
+		if !isFragment {
+			continue
+		}
+
+		target, found := v.targets[fragment]
+		switch {
+		case !found:
+			v.report(location, fmt.Sprintf("link %q (rel %q) does not resolve to a control, part or resource", link.Href, link.Rel))
+		case link.Rel == "reference" && target != targetResource:
+			v.report(location, fmt.Sprintf("reference link %q does not point to a back-matter resource", link.Href))
+		}
+	}
+}
+
+func (v *linkValidator) report(location, message string) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{
+		Severity: v.severity,
+		Location: location,
+		Message:  message,
+	})
+}
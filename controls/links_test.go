@@ -0,0 +1,47 @@
+package controls
+
+import (
+	"os"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/ossf/gemara/layer1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateLinks(t *testing.T) {
+	file, err := os.Open("./testdata/800-161.yml")
+	require.NoError(t, err)
+
+	var guidance layer1.GuidanceDocument
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&guidance)
+	require.NoError(t, err)
+
+	guidance.Categories[0].Guidelines[0].ExternalReferences = append(
+		guidance.Categories[0].Guidelines[0].ExternalReferences,
+		"unknown-resource",
+	)
+	guidance.Categories[0].Guidelines[0].SeeAlso = []string{"AU-6(9)"}
+
+	// Unknown references are dropped and reported
+	catalog, err := ToCatalog(guidance)
+	var unknownErr *UnknownReferenceError
+	require.ErrorAs(t, err, &unknownErr)
+	require.Equal(t, []Diagnostic{{
+		Severity: SeverityError,
+		Location: "ac-5",
+		Message:  `external reference "unknown-resource" is not a document resource and was dropped`,
+	}}, unknownErr.Diagnostics)
+
+	diagnostics := ValidateLinks(catalog, SeverityError)
+	// SA-15 and SR-3 reference controls outside the catalog
+	require.Len(t, diagnostics, 6)
+	require.Equal(t, "sa-15", diagnostics[0].Location)
+	require.Equal(t, `link "#sa-15.1" (rel "related") does not resolve to a control, part or resource`, diagnostics[0].Message)
+
+	diagnostics = ValidateLinks(catalog, SeverityWarning)
+	for _, diagnostic := range diagnostics {
+		require.Equal(t, SeverityWarning, diagnostic.Severity)
+	}
+}
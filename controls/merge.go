@@ -0,0 +1,284 @@
+package controls
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/models"
+	"github.com/ossf/gemara/layer1"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+)
+
+// MergeToCatalog converts multiple Layer 1 guidance documents into a single OSCAL Catalog with a
+// top-level group per document. When ids (controls, parts, params or groups) of a document collide with
+// ids of a previously merged document, they are namespaced with the document id (e.g. internal_ac-5)
+// and all fragment links and param inserts within that document are updated. Back-matter resources
+// are deduplicated by URL and metadata parties, roles and responsible parties are merged.
+//
+// When documents reference unknown external resources, the merged catalog is returned along with
+// an *UnknownReferenceError holding the diagnostics of every document.
+func MergeToCatalog(title, version string, documents []layer1.GuidanceDocument, opts ...Option) (oscalTypes.Catalog, error) {
+	options := applyOptions(opts)
+	uuids := options.UUIDs()
+
+	metadata := models.NewSampleMetadata()
+	metadata.Title = title
+	metadata.Version = version
+	metadata.LastModified = options.Now()
+
+	merger := catalogMerger{
+		ids:               make(map[string]bool),
+		resourcesByUrl:    make(map[string]string),
+		partiesByName:     make(map[string]string),
+		roles:             make(map[string]bool),
+		responsibleByRole: make(map[string]int),
+	}
+
+	var groups []oscalTypes.Group
+	var unknownReferences []Diagnostic
+	for i, document := range documents {
+		catalog, err := ToCatalog(document, opts...)
+		var unknownErr *UnknownReferenceError
+		if errors.As(err, &unknownErr) {
+			unknownReferences = append(unknownReferences, unknownErr.Diagnostics...)
+		} else if err != nil {
+			return oscalTypes.Catalog{}, fmt.Errorf("failed to convert guidance document %s: %w", document.Metadata.Id, err)
+		}
+
+		// Use the most recent modification time of all documents
+		if i == 0 || catalog.Metadata.LastModified.After(metadata.LastModified) {
+			metadata.LastModified = catalog.Metadata.LastModified
+		}
+
+		renames := merger.mergeBackMatter(catalog.BackMatter)
+		merger.mergeMetadata(catalog.Metadata)
+
+		documentGroup := oscalTypes.Group{
+			ID:     utils.ToToken(document.Metadata.Id),
+			Title:  document.Metadata.Title,
+			Groups: catalog.Groups,
+		}
+		merger.namespace(documentGroup.ID, &documentGroup, renames)
+		groups = append(groups, documentGroup)
+	}
+
+	metadata.Parties = utils.NilIfEmpty(&merger.parties)
+	metadata.Roles = utils.NilIfEmpty(&merger.roleList)
+	metadata.ResponsibleParties = utils.NilIfEmpty(&merger.responsible)
+
+	var backmatter *oscalTypes.BackMatter
+	if len(merger.resources) > 0 {
+		backmatter = &oscalTypes.BackMatter{Resources: &merger.resources}
+	}
+
+	catalog := oscalTypes.Catalog{
+		UUID:       uuids.Generate(title, version),
+		Metadata:   metadata,
+		Groups:     utils.NilIfEmpty(&groups),
+		BackMatter: backmatter,
+	}
+	if len(unknownReferences) > 0 {
+		return catalog, &UnknownReferenceError{Diagnostics: unknownReferences}
+	}
+	return catalog, nil
+}
+
+type catalogMerger struct {
+	// ids tracks all ids claimed by previously merged documents
+	ids map[string]bool
+
+	resources      []oscalTypes.Resource
+	resourcesByUrl map[string]string
+
+	parties       []oscalTypes.Party
+	partiesByName map[string]string
+
+	roleList []oscalTypes.Role
+	roles    map[string]bool
+
+	responsible       []oscalTypes.ResponsibleParty
+	responsibleByRole map[string]int
+}
+
+// mergeBackMatter adds resources not already present by URL and returns the UUID
+// renames for duplicate resources.
+func (m *catalogMerger) mergeBackMatter(backmatter *oscalTypes.BackMatter) map[string]string {
+	renames := make(map[string]string)
+	if backmatter == nil || backmatter.Resources == nil {
+		return renames
+	}
+	for _, resource := range *backmatter.Resources {
+		var url string
+		if resource.Rlinks != nil && len(*resource.Rlinks) > 0 {
+			url = (*resource.Rlinks)[0].Href
+		}
+		if url != "" {
+			if existing, found := m.resourcesByUrl[url]; found {
+				renames[resource.UUID] = existing
+				continue
+			}
+			m.resourcesByUrl[url] = resource.UUID
+		}
+		m.resources = append(m.resources, resource)
+	}
+	return renames
+}
+
+// mergeMetadata merges parties by type and name, roles by id and responsible parties by role id.
+func (m *catalogMerger) mergeMetadata(metadata oscalTypes.Metadata) {
+	partyRenames := make(map[string]string)
+	if metadata.Parties != nil {
+		for _, party := range *metadata.Parties {
+			key := fmt.Sprintf("%s/%s", party.Type, party.Name)
+			if existing, found := m.partiesByName[key]; found {
+				partyRenames[party.UUID] = existing
+				continue
+			}
+			m.partiesByName[key] = party.UUID
+			m.parties = append(m.parties, party)
+		}
+	}
+
+	if metadata.Roles != nil {
+		for _, role := range *metadata.Roles {
+			if m.roles[role.ID] {
+				continue
+			}
+			m.roles[role.ID] = true
+			m.roleList = append(m.roleList, role)
+		}
+	}
+
+	if metadata.ResponsibleParties == nil {
+		return
+	}
+	for _, responsible := range *metadata.ResponsibleParties {
+		index, found := m.responsibleByRole[responsible.RoleId]
+		if !found {
+			index = len(m.responsible)
+			m.responsibleByRole[responsible.RoleId] = index
+			m.responsible = append(m.responsible, oscalTypes.ResponsibleParty{RoleId: responsible.RoleId})
+		}
+		for _, partyUUID := range responsible.PartyUuids {
+			if renamed, ok := partyRenames[partyUUID]; ok {
+				partyUUID = renamed
+			}
+			if !slices.Contains(m.responsible[index].PartyUuids, partyUUID) {
+				m.responsible[index].PartyUuids = append(m.responsible[index].PartyUuids, partyUUID)
+			}
+		}
+	}
+}
+
+// namespace prefixes every id in the document group that collides with a previously merged
+// document and rewrites fragment links and param inserts to match.
+func (m *catalogMerger) namespace(prefix string, group *oscalTypes.Group, renames map[string]string) {
+	documentIds := make(map[string]bool)
+	collectGroupIds(*group, documentIds)
+	for id := range documentIds {
+		if m.ids[id] {
+			renames[id] = fmt.Sprintf("%s_%s", prefix, id)
+		}
+	}
+	for id := range documentIds {
+		if renamed, ok := renames[id]; ok {
+			id = renamed
+		}
+		m.ids[id] = true
+	}
+
+	if len(renames) > 0 {
+		renameGroup(group, renames)
+	}
+}
+
+func collectGroupIds(group oscalTypes.Group, ids map[string]bool) {
+	if group.ID != "" {
+		ids[group.ID] = true
+	}
+	targets := make(map[string]linkTarget)
+	collectGroupTargets([]oscalTypes.Group{group}, targets)
+	for id := range targets {
+		ids[id] = true
+	}
+}
+
+func renameGroup(group *oscalTypes.Group, renames map[string]string) {
+	group.ID = renamed(group.ID, renames)
+	renameLinks(group.Links, renames)
+	renameParams(group.Params, renames)
+	renameParts(group.Parts, renames)
+	if group.Controls != nil {
+		for i := range *group.Controls {
+			renameControl(&(*group.Controls)[i], renames)
+		}
+	}
+	if group.Groups != nil {
+		for i := range *group.Groups {
+			renameGroup(&(*group.Groups)[i], renames)
+		}
+	}
+}
+
+func renameControl(control *oscalTypes.Control, renames map[string]string) {
+	control.ID = renamed(control.ID, renames)
+	renameLinks(control.Links, renames)
+	renameParams(control.Params, renames)
+	renameParts(control.Parts, renames)
+	if control.Controls != nil {
+		for i := range *control.Controls {
+			renameControl(&(*control.Controls)[i], renames)
+		}
+	}
+}
+
+func renameParts(parts *[]oscalTypes.Part, renames map[string]string) {
+	if parts == nil {
+		return
+	}
+	for i := range *parts {
+		part := &(*parts)[i]
+		part.ID = renamed(part.ID, renames)
+		part.Prose = replaceParamPlaceholders(part.Prose, func(paramId string) (string, bool) {
+			newId, ok := renames[paramId]
+			if !ok {
+				return "", false
+			}
+			return ParamInsert(newId), true
+		})
+		renameLinks(part.Links, renames)
+		renameParts(part.Parts, renames)
+	}
+}
+
+func renameParams(params *[]oscalTypes.Parameter, renames map[string]string) {
+	if params == nil {
+		return
+	}
+	for i := range *params {
+		(*params)[i].ID = renamed((*params)[i].ID, renames)
+	}
+}
+
+func renameLinks(links *[]oscalTypes.Link, renames map[string]string) {
+	if links == nil {
+		return
+	}
+	for i := range *links {
+		link := &(*links)[i]
+		if fragment, isFragment := strings.CutPrefix(link.Href, "#"); isFragment {
+			link.Href = fmt.Sprintf("#%s", renamed(fragment, renames))
+		}
+	}
+}
+
+func renamed(id string, renames map[string]string) string {
+	if newId, ok := renames[id]; ok {
+		return newId
+	}
+	return id
+}
@@ -0,0 +1,158 @@
+package controls
+
+import (
+	"os"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/ossf/gemara/layer1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeToCatalog(t *testing.T) {
+	file, err := os.Open("./testdata/800-161.yml")
+	require.NoError(t, err)
+
+	var guidance layer1.GuidanceDocument
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&guidance)
+	require.NoError(t, err)
+
+	internal := layer1.GuidanceDocument{
+		Metadata: layer1.Metadata{
+			Id:              "internal",
+			Title:           "Internal Guidance",
+			Author:          "Compliance Team",
+			PublicationDate: "2025-08-01",
+			LastModified:    "2025-08-01 10:00:00",
+			Resources: []layer1.ResourceReference{
+				{
+					Id:    "800-161",
+					Title: "NIST SP 800-161r1",
+					Url:   "https://doi.org/10.6028/NIST.SP.800-161r1-upd1",
+				},
+			},
+		},
+		Categories: []layer1.Category{
+			{
+				Id:    "AC",
+				Title: "Access Control",
+				Guidelines: []layer1.Guideline{
+					{
+						Id:                 "AC-5",
+						Title:              "Separation of Duties",
+						Objective:          "Separate duties for code promotion.",
+						SeeAlso:            []string{"AC-6"},
+						ExternalReferences: []string{"800-161"},
+					},
+					{
+						Id:    "AC-6",
+						Title: "Least Privilege",
+					},
+				},
+			},
+		},
+	}
+
+	catalog, err := MergeToCatalog("Merged Guidance", "1.0.0", []layer1.GuidanceDocument{guidance, internal})
+	require.NoError(t, err)
+
+	groups := *catalog.Groups
+	require.Len(t, groups, 2)
+	require.Equal(t, "nist-sp-800-161r1-custom", groups[0].ID)
+	require.Equal(t, "internal", groups[1].ID)
+
+	internalGroup := (*groups[1].Groups)[0]
+	require.Equal(t, "internal_AC", internalGroup.ID)
+	controls := *internalGroup.Controls
+	require.Equal(t, "internal_ac-5", controls[0].ID)
+	require.Equal(t, "internal_ac-5_smt", (*controls[0].Parts)[0].ID)
+	// AC-6 does not collide and keeps its id
+	require.Equal(t, "ac-6", controls[1].ID)
+
+	resources := *catalog.BackMatter.Resources
+	require.Len(t, resources, 1)
+	links := *controls[0].Links
+	require.Equal(t, "#ac-6", links[0].Href)
+	require.Equal(t, "#"+resources[0].UUID, links[1].Href)
+
+	require.Len(t, *catalog.Metadata.Parties, 2)
+	require.Len(t, *catalog.Metadata.Roles, 1)
+	responsible := *catalog.Metadata.ResponsibleParties
+	require.Len(t, responsible, 1)
+	require.Len(t, responsible[0].PartyUuids, 2)
+
+	oscalModels := oscalTypes.OscalModels{
+		Catalog: &catalog,
+	}
+
+	validator := validation.NewSchemaValidator()
+	err = validator.Validate(oscalModels)
+	require.NoError(t, err)
+}
+
+func TestMergeToCatalog_UnknownReference(t *testing.T) {
+	file, err := os.Open("./testdata/800-161.yml")
+	require.NoError(t, err)
+
+	var guidance layer1.GuidanceDocument
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&guidance)
+	require.NoError(t, err)
+
+	internal := layer1.GuidanceDocument{
+		Metadata: layer1.Metadata{
+			Id:    "internal",
+			Title: "Internal Guidance",
+		},
+		Categories: []layer1.Category{
+			{
+				Id:    "AC",
+				Title: "Access Control",
+				Guidelines: []layer1.Guideline{
+					{
+						Id:                 "AC-6",
+						Title:              "Least Privilege",
+						ExternalReferences: []string{"unknown-resource"},
+					},
+				},
+			},
+		},
+	}
+
+	// The merged catalog is still returned with the dropped reference reported
+	catalog, err := MergeToCatalog("Merged Guidance", "1.0.0", []layer1.GuidanceDocument{guidance, internal},
+		WithUnknownReferenceSeverity(SeverityWarning))
+	var unknownErr *UnknownReferenceError
+	require.ErrorAs(t, err, &unknownErr)
+	require.Equal(t, []Diagnostic{{
+		Severity: SeverityWarning,
+		Location: "ac-6",
+		Message:  `external reference "unknown-resource" is not a document resource and was dropped`,
+	}}, unknownErr.Diagnostics)
+
+	groups := *catalog.Groups
+	require.Len(t, groups, 2)
+	control := (*(*groups[1].Groups)[0].Controls)[0]
+	require.Equal(t, "ac-6", control.ID)
+	require.Nil(t, control.Links)
+}
+
+func TestRenameParts(t *testing.T) {
+	parts := []oscalTypes.Part{
+		{
+			ID:    "ac-5_smt",
+			Prose: "Review {{ insert: param, ac-5_prm_1 }} and {{insert: param,ac-5_prm_2}} every {{ period }}.",
+		},
+	}
+	renames := map[string]string{
+		"ac-5_smt":   "internal_ac-5_smt",
+		"ac-5_prm_1": "internal_ac-5_prm_1",
+		"ac-5_prm_2": "internal_ac-5_prm_2",
+	}
+	renameParts(&parts, renames)
+	require.Equal(t, "internal_ac-5_smt", parts[0].ID)
+	require.Equal(t, "Review {{ insert: param, internal_ac-5_prm_1 }} and {{ insert: param, internal_ac-5_prm_2 }} every {{ period }}.", parts[0].Prose)
+}
@@ -0,0 +1,196 @@
+package controls
+
+import (
+	"slices"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/ossf/gemara/layer1"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+	"github.com/jpower432/gemara2oscal/vocabulary"
+)
+
+// Built-in metadata role ids.
+const (
+	RoleAuthor    = "author"
+	RolePublisher = "publisher"
+	RoleContact   = "contact"
+)
+
+// Party types.
+const (
+	PartyPerson       = "person"
+	PartyOrganization = "organization"
+)
+
+var builtinRoles = []oscalTypes.Role{
+	{
+		ID:          RoleAuthor,
+		Title:       "Author",
+		Description: "Author of the guidance document",
+	},
+	{
+		ID:          RolePublisher,
+		Title:       "Publisher",
+		Description: "Organization responsible for publishing the guidance document",
+	},
+	{
+		ID:          RoleContact,
+		Title:       "Contact",
+		Description: "Point of contact for questions about the guidance document",
+	},
+}
+
+// Party is a person or organization added to the catalog metadata with the roles it holds.
+type Party struct {
+	Name string
+	// Type is PartyPerson or PartyOrganization. The default is PartyPerson.
+	Type           string
+	ShortName      string
+	EmailAddresses []string
+	// Roles are role ids, either built-in (e.g. RolePublisher) or added with WithRoles.
+	Roles []string
+}
+
+// metadataParties returns the parties, roles and responsible parties for the guidance document.
+// Authors are read from the semicolon separated author field. Parties added with WithParties
+// that match an author by name are merged with the author party.
+func metadataParties(documentMetadata layer1.Metadata, options options) ([]oscalTypes.Party, []oscalTypes.Role, []oscalTypes.ResponsibleParty) {
+	uuids := options.UUIDs()
+
+	var parties []Party
+	for _, name := range splitAuthors(documentMetadata.Author) {
+		parties = append(parties, Party{
+			Name:  name,
+			Type:  options.authorType,
+			Roles: []string{RoleAuthor},
+		})
+	}
+	for _, party := range options.parties {
+		index := slices.IndexFunc(parties, func(existing Party) bool {
+			return strings.EqualFold(existing.Name, party.Name)
+		})
+		if index < 0 {
+			parties = append(parties, party)
+			continue
+		}
+		existing := &parties[index]
+		if party.Type != "" {
+			existing.Type = party.Type
+		}
+		if party.ShortName != "" {
+			existing.ShortName = party.ShortName
+		}
+		existing.EmailAddresses = append(existing.EmailAddresses, party.EmailAddresses...)
+		for _, role := range party.Roles {
+			if !slices.Contains(existing.Roles, role) {
+				existing.Roles = append(existing.Roles, role)
+			}
+		}
+	}
+
+	var oscalParties []oscalTypes.Party
+	partiesByRole := make(map[string][]string)
+	var roleOrder []string
+	for _, role := range builtinRoles {
+		roleOrder = append(roleOrder, role.ID)
+	}
+	for _, party := range parties {
+		partyType := party.Type
+		if partyType == "" {
+			partyType = PartyPerson
+		}
+		oscalParty := oscalTypes.Party{
+			UUID:           uuids.Generate(documentMetadata.Id, "party", party.Name),
+			Type:           partyType,
+			Name:           party.Name,
+			ShortName:      party.ShortName,
+			EmailAddresses: utils.NilIfEmpty(&party.EmailAddresses),
+		}
+		oscalParties = append(oscalParties, oscalParty)
+		for _, role := range party.Roles {
+			if !slices.Contains(roleOrder, role) {
+				roleOrder = append(roleOrder, role)
+			}
+			partiesByRole[role] = append(partiesByRole[role], oscalParty.UUID)
+		}
+	}
+
+	definitions := make(map[string]oscalTypes.Role)
+	for _, role := range builtinRoles {
+		definitions[role.ID] = role
+	}
+	for _, role := range options.roles {
+		definitions[role.ID] = role
+		if !slices.Contains(roleOrder, role.ID) {
+			roleOrder = append(roleOrder, role.ID)
+		}
+	}
+
+	var roles []oscalTypes.Role
+	var responsibleParties []oscalTypes.ResponsibleParty
+	for _, roleId := range roleOrder {
+		role, defined := definitions[roleId]
+		if !defined {
+			// Roles without a definition are titled with their id
+			role = oscalTypes.Role{ID: roleId, Title: roleId}
+		}
+		injected := slices.ContainsFunc(options.roles, func(injected oscalTypes.Role) bool {
+			return injected.ID == roleId
+		})
+		if len(partiesByRole[roleId]) == 0 && !injected {
+			continue
+		}
+		roles = append(roles, role)
+		if len(partiesByRole[roleId]) > 0 {
+			responsibleParties = append(responsibleParties, oscalTypes.ResponsibleParty{
+				RoleId:     roleId,
+				PartyUuids: partiesByRole[roleId],
+			})
+		}
+	}
+	return oscalParties, roles, responsibleParties
+}
+
+// authorSeparator separates names in the Layer 1 author field.
+const authorSeparator = ";"
+
+// splitAuthors splits the Layer 1 author field into individual names. Only semicolons separate
+// names, since commas are common within names (e.g. "Acme, Inc." or "Doe, Jane").
+func splitAuthors(author string) []string {
+	var names []string
+	for _, name := range strings.Split(author, authorSeparator) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// metadataProps returns props for the document type and applicability of the guidance document.
+func metadataProps(documentMetadata layer1.Metadata, vocab vocabulary.Vocabulary) []oscalTypes.Property {
+	var props []oscalTypes.Property
+	addProp := func(name, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			props = append(props, vocab.Prop(name, value))
+		}
+	}
+
+	addProp("document-type", string(documentMetadata.DocumentType))
+	if applicability := documentMetadata.Applicabilty; applicability != nil {
+		for _, jurisdiction := range applicability.Jurisdictions {
+			addProp("jurisdiction", jurisdiction)
+		}
+		for _, domain := range applicability.TechnologyDomains {
+			addProp("technology-domain", domain)
+		}
+		for _, sector := range applicability.IndustrySectors {
+			addProp("industry-sector", sector)
+		}
+	}
+	for _, exemption := range documentMetadata.Exemptions {
+		addProp("exemption", exemption)
+	}
+	return props
+}
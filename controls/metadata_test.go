@@ -0,0 +1,84 @@
+package controls
+
+import (
+	"os"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/ossf/gemara/layer1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpower432/gemara2oscal/vocabulary"
+)
+
+func TestToCatalog_Metadata(t *testing.T) {
+	file, err := os.Open("./testdata/800-161.yml")
+	require.NoError(t, err)
+
+	var guidance layer1.GuidanceDocument
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&guidance)
+	require.NoError(t, err)
+
+	guidance.Metadata.Author = "OpenSSF; Doe, Jane"
+	guidance.Metadata.Applicabilty = &layer1.Applicability{
+		TechnologyDomains: []string{"Software Supply Chain"},
+	}
+
+	catalog, err := ToCatalog(guidance,
+		WithDeterministicUUIDs(),
+		WithParties(
+			Party{Name: "OpenSSF", Type: PartyOrganization, Roles: []string{RolePublisher}},
+			Party{Name: "Security Team", EmailAddresses: []string{"security@example.com"}, Roles: []string{RoleContact}},
+		),
+		WithRoles(oscalTypes.Role{ID: "reviewer", Title: "Reviewer"}),
+	)
+	require.NoError(t, err)
+
+	metadata := catalog.Metadata
+	require.Equal(t, guidance.Metadata.Description, metadata.Remarks)
+
+	parties := *metadata.Parties
+	require.Len(t, parties, 3)
+	require.Equal(t, "OpenSSF", parties[0].Name)
+	require.Equal(t, PartyOrganization, parties[0].Type)
+	require.Equal(t, "Doe, Jane", parties[1].Name)
+	require.Equal(t, PartyPerson, parties[1].Type)
+	require.Equal(t, []string{"security@example.com"}, *parties[2].EmailAddresses)
+
+	var roleIds []string
+	for _, role := range *metadata.Roles {
+		roleIds = append(roleIds, role.ID)
+	}
+	require.Equal(t, []string{RoleAuthor, RolePublisher, RoleContact, "reviewer"}, roleIds)
+
+	require.Equal(t, []oscalTypes.ResponsibleParty{
+		{RoleId: RoleAuthor, PartyUuids: []string{parties[0].UUID, parties[1].UUID}},
+		{RoleId: RolePublisher, PartyUuids: []string{parties[0].UUID}},
+		{RoleId: RoleContact, PartyUuids: []string{parties[2].UUID}},
+	}, *metadata.ResponsibleParties)
+
+	validator := validation.NewSchemaValidator()
+	err = validator.Validate(oscalTypes.OscalModels{Catalog: &catalog})
+	require.NoError(t, err)
+
+	got, diagnostics := FromCatalog(catalog)
+	require.Empty(t, diagnostics)
+	require.Equal(t, guidance.Metadata.Author, got.Metadata.Author)
+	require.Equal(t, guidance.Metadata.Description, got.Metadata.Description)
+	require.Equal(t, guidance.Metadata.DocumentType, got.Metadata.DocumentType)
+	require.Equal(t, guidance.Metadata.Applicabilty, got.Metadata.Applicabilty)
+
+	// Props are only read in the namespace of the vocabulary
+	gemaraCatalog, err := ToCatalog(guidance, WithVocabulary(vocabulary.Gemara))
+	require.NoError(t, err)
+	got, diagnostics = FromCatalog(gemaraCatalog, WithVocabulary(vocabulary.Gemara))
+	require.Empty(t, diagnostics)
+	require.Equal(t, guidance.Metadata.DocumentType, got.Metadata.DocumentType)
+
+	got, diagnostics = FromCatalog(gemaraCatalog)
+	require.NotEmpty(t, diagnostics)
+	require.Empty(t, got.Metadata.DocumentType)
+}
@@ -0,0 +1,161 @@
+package controls
+
+import (
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+	"github.com/jpower432/gemara2oscal/normalize"
+	"github.com/jpower432/gemara2oscal/vocabulary"
+)
+
+type options struct {
+	utils.Reproducibility
+	normalizer normalize.Normalizer
+	references normalize.Table
+
+	resourceLoader ResourceLoader
+	resourceHashes bool
+	embedResources bool
+
+	authorType string
+	parties    []Party
+	roles      []oscalTypes.Role
+
+	dateLayouts         []string
+	defaultPublished    *time.Time
+	defaultLastModified *time.Time
+
+	vocabulary vocabulary.Vocabulary
+
+	unknownReferenceSeverity Severity
+}
+
+// Option configures catalog conversion.
+type Option func(opts *options)
+
+// WithDeterministicUUIDs makes repeated conversions of a document produce identical catalogs.
+// UUIDs are derived from Gemara identifiers and dates missing from the document are set to the
+// WithTimestamp time or the Unix epoch.
+func WithDeterministicUUIDs() Option {
+	return func(opts *options) {
+		opts.Deterministic = true
+	}
+}
+
+// WithTimestamp sets the time used for generated dates in deterministic mode.
+func WithTimestamp(timestamp time.Time) Option {
+	return func(opts *options) {
+		opts.Timestamp = &timestamp
+	}
+}
+
+// WithNormalizer sets the Normalizer used to convert guideline, base guideline, see-also and
+// Layer 2 control ids into OSCAL control ids. The default is normalize.NIST80053.
+func WithNormalizer(normalizer normalize.Normalizer) Option {
+	return func(opts *options) {
+		opts.normalizer = normalizer
+	}
+}
+
+// WithReferenceNormalizer sets the Normalizer used for identifiers of the shared guidance
+// document with the given reference id when resolving catalogs. The default is normalize.NIST80053.
+func WithReferenceNormalizer(referenceId string, normalizer normalize.Normalizer) Option {
+	return func(opts *options) {
+		opts.references.Set(referenceId, normalizer)
+	}
+}
+
+// WithResourceLoader sets the ResourceLoader used to read resource content for hashing and
+// embedding. The default is LocalResources relative to the working directory.
+func WithResourceLoader(loader ResourceLoader) Option {
+	return func(opts *options) {
+		opts.resourceLoader = loader
+	}
+}
+
+// WithResourceHashes adds a SHA-256 hash to the rlink of every resource whose content can be loaded.
+func WithResourceHashes() Option {
+	return func(opts *options) {
+		opts.resourceHashes = true
+	}
+}
+
+// WithEmbeddedResources embeds the content of every resource that can be loaded (e.g. local PDF
+// or Markdown policies) into the back-matter as base64.
+func WithEmbeddedResources() Option {
+	return func(opts *options) {
+		opts.embedResources = true
+	}
+}
+
+// WithAuthorType sets the party type (PartyPerson or PartyOrganization) of document authors
+// that are not configured with WithParties. The default is PartyPerson.
+func WithAuthorType(partyType string) Option {
+	return func(opts *options) {
+		opts.authorType = partyType
+	}
+}
+
+// WithParties adds parties to the catalog metadata (e.g. a publisher or contact). A party with the
+// same name as a document author is merged with the author party.
+func WithParties(parties ...Party) Option {
+	return func(opts *options) {
+		opts.parties = append(opts.parties, parties...)
+	}
+}
+
+// WithRoles adds roles to the catalog metadata or replaces the definition of a built-in role.
+func WithRoles(roles ...oscalTypes.Role) Option {
+	return func(opts *options) {
+		opts.roles = append(opts.roles, roles...)
+	}
+}
+
+// WithDateLayouts adds time layouts that are tried before the default layouts when parsing
+// metadata dates (e.g. "January 2, 2006").
+func WithDateLayouts(layouts ...string) Option {
+	return func(opts *options) {
+		opts.dateLayouts = append(opts.dateLayouts, layouts...)
+	}
+}
+
+// WithDefaultPublicationDate sets the publication date used when a document has none.
+func WithDefaultPublicationDate(published time.Time) Option {
+	return func(opts *options) {
+		opts.defaultPublished = &published
+	}
+}
+
+// WithDefaultLastModified sets the last modified date used when a document has none. Without
+// a default, the publication date or the current time is used.
+func WithDefaultLastModified(lastModified time.Time) Option {
+	return func(opts *options) {
+		opts.defaultLastModified = &lastModified
+	}
+}
+
+// WithVocabulary sets the vocabulary of the emitted props (e.g. the resource id props). The default
+// is vocabulary.Trestle.
+func WithVocabulary(vocab vocabulary.Vocabulary) Option {
+	return func(opts *options) {
+		opts.vocabulary = vocab
+	}
+}
+
+// WithUnknownReferenceSeverity sets the severity of the diagnostics reported for external
+// references to resources that are not in the document metadata. The default is SeverityError.
+func WithUnknownReferenceSeverity(severity Severity) Option {
+	return func(opts *options) {
+		opts.unknownReferenceSeverity = severity
+	}
+}
+
+func applyOptions(opts []Option) options {
+	o := options{normalizer: normalize.NIST80053, vocabulary: vocabulary.Trestle, unknownReferenceSeverity: SeverityError}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,117 @@
+package controls
+
+import (
+	"fmt"
+	"regexp"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/ossf/gemara/layer2"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+)
+
+// ParamInsert returns the OSCAL insert placeholder for the given parameter id.
+func ParamInsert(paramId string) string {
+	return fmt.Sprintf("{{ insert: param, %s }}", paramId)
+}
+
+// paramPlaceholderRe matches OSCAL param inserts ({{ insert: param, id }}) and the shorthand
+// placeholder {{ id }} that Layer 2 prose can use to position a parameter.
+var paramPlaceholderRe = regexp.MustCompile(`{{\s*(?:insert:\s*param,\s*)?([A-Za-z_][\w.\-]*)\s*}}`)
+
+// replaceParamPlaceholders replaces every param placeholder in the prose with the result of replace
+// for its param id. Placeholders are kept unchanged when replace returns false.
+func replaceParamPlaceholders(prose string, replace func(paramId string) (string, bool)) string {
+	return paramPlaceholderRe.ReplaceAllStringFunc(prose, func(placeholder string) string {
+		if replaced, ok := replace(paramPlaceholderRe.FindStringSubmatch(placeholder)[1]); ok {
+			return replaced
+		}
+		return placeholder
+	})
+}
+
+// insertParams adds insert placeholders for each parameter to the given prose. Placeholders for
+// the parameter already written in the prose (e.g. {{ min_approvals }}) are converted into inserts.
+// Otherwise, a sentence with the parameter label and insert is appended.
+func insertParams(prose string, parameters []layer2.Parameter) string {
+	placed := make(map[string]bool)
+	for _, parameter := range parameters {
+		placed[parameter.Id] = false
+	}
+	prose = replaceParamPlaceholders(prose, func(paramId string) (string, bool) {
+		if _, found := placed[paramId]; !found {
+			return "", false
+		}
+		placed[paramId] = true
+		return ParamInsert(paramId), true
+	})
+
+	for _, parameter := range parameters {
+		if placed[parameter.Id] {
+			continue
+		}
+		placed[parameter.Id] = true
+		label := singleLine(parameter.Description)
+		if label == "" {
+			label = parameter.Id
+		}
+		prose = fmt.Sprintf("%s\n\n%s: %s.", prose, label, ParamInsert(parameter.Id))
+	}
+	return prose
+}
+
+func parameterToParam(parameter layer2.Parameter) oscalTypes.Parameter {
+	param := oscalTypes.Parameter{
+		ID:    parameter.Id,
+		Label: singleLine(parameter.Description),
+	}
+
+	if parameter.Default == nil {
+		return param
+	}
+
+	var values []string
+	switch defaultValue := parameter.Default.(type) {
+	case []any:
+		for _, value := range defaultValue {
+			values = append(values, utils.ConvertToString(value))
+		}
+	default:
+		values = append(values, utils.ConvertToString(defaultValue))
+	}
+	param.Values = utils.NilIfEmpty(&values)
+
+	switch parameter.Default.(type) {
+	case bool:
+		param.Select = &oscalTypes.ParameterSelection{
+			Choice: &[]string{"true", "false"},
+		}
+	default:
+		if constraint, ok := constraintFor(parameter.Default); ok {
+			param.Constraints = &[]oscalTypes.ParameterConstraint{constraint}
+		}
+	}
+	return param
+}
+
+// constraintFor infers a parameter constraint from the type of the recommended default value.
+func constraintFor(defaultValue any) (oscalTypes.ParameterConstraint, bool) {
+	switch defaultValue.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return oscalTypes.ParameterConstraint{
+			Description: "Value must be an integer",
+			Tests: &[]oscalTypes.ConstraintTest{
+				{Expression: "matches(., '^-?[0-9]+$')"},
+			},
+		}, true
+	case float32, float64:
+		return oscalTypes.ParameterConstraint{
+			Description: "Value must be a number",
+			Tests: &[]oscalTypes.ConstraintTest{
+				{Expression: "matches(., '^-?[0-9]+(\\.[0-9]+)?$')"},
+			},
+		}, true
+	default:
+		return oscalTypes.ParameterConstraint{}, false
+	}
+}
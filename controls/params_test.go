@@ -0,0 +1,119 @@
+package controls
+
+import (
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/ossf/gemara/layer2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertParams(t *testing.T) {
+	tests := []struct {
+		name       string
+		prose      string
+		parameters []layer2.Parameter
+		wantProse  string
+	}{
+		{
+			name:      "Success/NoParameters",
+			prose:     "Require approvals.",
+			wantProse: "Require approvals.",
+		},
+		{
+			name:  "Success/ReplacePlaceholder",
+			prose: "Require at least {{ min_approvals }} approvals.",
+			parameters: []layer2.Parameter{
+				{Id: "min_approvals", Description: "Minimum approvals"},
+			},
+			wantProse: "Require at least {{ insert: param, min_approvals }} approvals.",
+		},
+		{
+			name:  "Success/KeepInsert",
+			prose: "Require at least {{insert: param, min_approvals}} approvals.",
+			parameters: []layer2.Parameter{
+				{Id: "min_approvals", Description: "Minimum approvals"},
+			},
+			wantProse: "Require at least {{ insert: param, min_approvals }} approvals.",
+		},
+		{
+			name:  "Success/ParameterIdInText",
+			prose: "Require approvals before merging.",
+			parameters: []layer2.Parameter{
+				{Id: "approvals", Description: "Minimum approvals"},
+			},
+			wantProse: "Require approvals before merging.\n\nMinimum approvals: {{ insert: param, approvals }}.",
+		},
+		{
+			name:  "Success/UnknownPlaceholder",
+			prose: "Require {{ reviewers }} to approve.",
+			parameters: []layer2.Parameter{
+				{Id: "min_approvals", Description: "Minimum approvals"},
+			},
+			wantProse: "Require {{ reviewers }} to approve.\n\nMinimum approvals: {{ insert: param, min_approvals }}.",
+		},
+		{
+			name:  "Success/Append",
+			prose: "Require approvals.",
+			parameters: []layer2.Parameter{
+				{Id: "min_approvals", Description: "Minimum approvals"},
+			},
+			wantProse: "Require approvals.\n\nMinimum approvals: {{ insert: param, min_approvals }}.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantProse, insertParams(tt.prose, tt.parameters))
+		})
+	}
+}
+
+func TestParameterToParam(t *testing.T) {
+	tests := []struct {
+		name      string
+		parameter layer2.Parameter
+		wantParam oscalTypes.Parameter
+	}{
+		{
+			name:      "Success/NoDefault",
+			parameter: layer2.Parameter{Id: "branch", Description: "Primary branch"},
+			wantParam: oscalTypes.Parameter{ID: "branch", Label: "Primary branch"},
+		},
+		{
+			name:      "Success/Boolean",
+			parameter: layer2.Parameter{Id: "signed", Description: "Require signed commits", Default: true},
+			wantParam: oscalTypes.Parameter{
+				ID:     "signed",
+				Label:  "Require signed commits",
+				Values: &[]string{"true"},
+				Select: &oscalTypes.ParameterSelection{Choice: &[]string{"true", "false"}},
+			},
+		},
+		{
+			name:      "Success/List",
+			parameter: layer2.Parameter{Id: "branches", Default: []any{"main", "release"}},
+			wantParam: oscalTypes.Parameter{ID: "branches", Values: &[]string{"main", "release"}},
+		},
+		{
+			name:      "Success/Integer",
+			parameter: layer2.Parameter{Id: "approvals", Default: 1},
+			wantParam: oscalTypes.Parameter{
+				ID:     "approvals",
+				Values: &[]string{"1"},
+				Constraints: &[]oscalTypes.ParameterConstraint{
+					{
+						Description: "Value must be an integer",
+						Tests:       &[]oscalTypes.ConstraintTest{{Expression: "matches(., '^-?[0-9]+$')"}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantParam, parameterToParam(tt.parameter))
+		})
+	}
+}
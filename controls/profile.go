@@ -0,0 +1,132 @@
+package controls
+
+import (
+	"fmt"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/models"
+	"github.com/ossf/gemara/layer2"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+)
+
+// Layer2ToProfiles generates one OSCAL Profile per applicability category of a Layer 2 catalog.
+// Each profile imports the OSCAL catalog at catalogHref (generated by Layer2ToCatalog with the same
+// options) and includes every control with at least one assessment requirement applicable to the
+// category. Statement items of requirements that do not apply to the category are removed with
+// alterations. Assessment requirements may reference categories by id or title.
+//
+// Categories without any applicable requirements do not produce a profile. An error is returned
+// when a requirement references an unknown applicability category.
+func Layer2ToProfiles(catalog layer2.Catalog, catalogHref string, opts ...Option) ([]oscalTypes.Profile, error) {
+	options := applyOptions(opts)
+	uuids := options.UUIDs()
+
+	categories := make(map[string]string, len(catalog.Metadata.ApplicabilityCategories))
+	for _, category := range catalog.Metadata.ApplicabilityCategories {
+		categories[category.Id] = category.Id
+		categories[category.Title] = category.Id
+	}
+
+	type selection struct {
+		controlIds []string
+		// removals maps control ids to the statement items that do not apply
+		removals map[string][]string
+	}
+	selections := make(map[string]*selection, len(catalog.Metadata.ApplicabilityCategories))
+	for _, category := range catalog.Metadata.ApplicabilityCategories {
+		selections[category.Id] = &selection{removals: make(map[string][]string)}
+	}
+
+	for _, family := range catalog.ControlFamilies {
+		for _, control := range family.Controls {
+			controlId := options.normalizer.Normalize(control.Id)
+
+			// applicable maps category ids to the applicable requirement item ids
+			applicable := make(map[string]map[string]bool)
+			var itemIds []string
+			for _, requirement := range control.AssessmentRequirements {
+				itemId := fmt.Sprintf("%s_smt.%s", controlId, requirementSuffix(controlId, options.normalizer.Normalize(requirement.Id)))
+				itemIds = append(itemIds, itemId)
+				for _, reference := range requirement.Applicability {
+					categoryId, found := categories[strings.TrimSpace(reference)]
+					if !found {
+						return nil, fmt.Errorf("assessment requirement %s references unknown applicability category %q", requirement.Id, reference)
+					}
+					if applicable[categoryId] == nil {
+						applicable[categoryId] = make(map[string]bool)
+					}
+					applicable[categoryId][itemId] = true
+				}
+			}
+
+			for categoryId, items := range applicable {
+				selected := selections[categoryId]
+				selected.controlIds = append(selected.controlIds, controlId)
+				for _, itemId := range itemIds {
+					if !items[itemId] {
+						selected.removals[controlId] = append(selected.removals[controlId], itemId)
+					}
+				}
+			}
+		}
+	}
+
+	var profiles []oscalTypes.Profile
+	for _, category := range catalog.Metadata.ApplicabilityCategories {
+		selected := selections[category.Id]
+		if len(selected.controlIds) == 0 {
+			continue
+		}
+
+		metadata := models.NewSampleMetadata()
+		metadata.Title = fmt.Sprintf("%s: %s", catalog.Metadata.Title, category.Title)
+		metadata.Remarks = strings.TrimSpace(category.Description)
+		if catalog.Metadata.Version != "" {
+			metadata.Version = catalog.Metadata.Version
+		}
+		_, lastModified, err := options.metadataDates("", catalog.Metadata.LastModified)
+		if err != nil {
+			return nil, err
+		}
+		metadata.LastModified = lastModified
+
+		var alters []oscalTypes.Alteration
+		for _, controlId := range selected.controlIds {
+			itemIds := selected.removals[controlId]
+			if len(itemIds) == 0 {
+				continue
+			}
+			removes := make([]oscalTypes.Removal, 0, len(itemIds))
+			for _, itemId := range itemIds {
+				removes = append(removes, oscalTypes.Removal{ById: itemId})
+			}
+			alters = append(alters, oscalTypes.Alteration{
+				ControlId: controlId,
+				Removes:   &removes,
+			})
+		}
+
+		var modify *oscalTypes.Modify
+		if len(alters) > 0 {
+			modify = &oscalTypes.Modify{Alters: &alters}
+		}
+
+		profiles = append(profiles, oscalTypes.Profile{
+			UUID:     uuids.Generate(catalog.Metadata.Id, "profile", category.Id),
+			Metadata: metadata,
+			Imports: []oscalTypes.Import{
+				{
+					Href: catalogHref,
+					IncludeControls: &[]oscalTypes.SelectControlById{
+						{WithIds: utils.NilIfEmpty(&selected.controlIds)},
+					},
+				},
+			},
+			Merge:  &oscalTypes.Merge{AsIs: true},
+			Modify: modify,
+		})
+	}
+	return profiles, nil
+}
@@ -0,0 +1,68 @@
+package controls
+
+import (
+	"os"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/ossf/gemara/layer2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayer2ToProfiles(t *testing.T) {
+	file, err := os.Open("../component/testdata/good-osps.yml")
+	require.NoError(t, err)
+
+	var layer2Catalog layer2.Catalog
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&layer2Catalog)
+	require.NoError(t, err)
+
+	// Add a requirement that applies at a lower maturity level, referenced by category id
+	control := &layer2Catalog.ControlFamilies[0].Controls[0]
+	control.AssessmentRequirements = append(control.AssessmentRequirements, layer2.AssessmentRequirement{
+		Id:            "OSPS-QA-07.02",
+		Text:          "The project MUST document its review process.",
+		Applicability: []string{"Maturity2", "Maturity3"},
+	})
+
+	profiles, err := Layer2ToProfiles(layer2Catalog, "catalog.json", WithDeterministicUUIDs())
+	require.NoError(t, err)
+	// No requirement applies at Maturity Level 1
+	require.Len(t, profiles, 2)
+
+	maturity2 := profiles[0]
+	require.Contains(t, maturity2.Metadata.Title, "Maturity Level 2")
+	require.Len(t, maturity2.Imports, 1)
+	require.Equal(t, "catalog.json", maturity2.Imports[0].Href)
+	require.Equal(t, []string{"osps-qa-07"}, *(*maturity2.Imports[0].IncludeControls)[0].WithIds)
+	require.NotNil(t, maturity2.Modify)
+	alters := *maturity2.Modify.Alters
+	require.Len(t, alters, 1)
+	require.Equal(t, "osps-qa-07", alters[0].ControlId)
+	require.Equal(t, []oscalTypes.Removal{{ById: "osps-qa-07_smt.01"}}, *alters[0].Removes)
+
+	maturity3 := profiles[1]
+	require.Contains(t, maturity3.Metadata.Title, "Maturity Level 3")
+	require.Equal(t, []string{"osps-qa-07"}, *(*maturity3.Imports[0].IncludeControls)[0].WithIds)
+	require.Nil(t, maturity3.Modify)
+	require.NotEqual(t, maturity2.UUID, maturity3.UUID)
+
+	// Removed items must exist in the generated catalog
+	catalog, err := Layer2ToCatalog(layer2Catalog)
+	require.NoError(t, err)
+	items := *(*(*(*catalog.Groups)[0].Controls)[0].Parts)[0].Parts
+	require.Equal(t, "osps-qa-07_smt.01", items[0].ID)
+
+	validator := validation.NewSchemaValidator()
+	for _, profile := range profiles {
+		err = validator.Validate(oscalTypes.OscalModels{Profile: &profile})
+		require.NoError(t, err)
+	}
+
+	control.AssessmentRequirements[1].Applicability = []string{"Maturity Level 4"}
+	_, err = Layer2ToProfiles(layer2Catalog, "catalog.json")
+	require.EqualError(t, err, `assessment requirement OSPS-QA-07.02 references unknown applicability category "Maturity Level 4"`)
+}
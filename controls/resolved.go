@@ -0,0 +1,277 @@
+package controls
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/ossf/gemara/layer1"
+
+	"github.com/jpower432/gemara2oscal/normalize"
+)
+
+const (
+	// ReferenceSeeAlso indicates the unresolved reference came from a guideline see-also entry.
+	ReferenceSeeAlso = "see-also"
+	// ReferenceSharedGuideline indicates the unresolved reference came from the document shared-guidelines.
+	ReferenceSharedGuideline = "shared-guideline"
+)
+
+// UnresolvedReference describes a guideline reference that could not be found
+// in the guidance document or any of the supplied shared documents.
+type UnresolvedReference struct {
+	// Source is the guideline id or document id holding the reference.
+	Source string
+	// ReferenceId is the id of the referenced document, if known.
+	ReferenceId string
+	// Identifier is the referenced guideline id.
+	Identifier string
+	// Kind is the type of reference (see-also or shared-guideline).
+	Kind string
+}
+
+func (u UnresolvedReference) String() string {
+	if u.ReferenceId != "" {
+		return fmt.Sprintf("%s: %s %s/%s not found", u.Source, u.Kind, u.ReferenceId, u.Identifier)
+	}
+	return fmt.Sprintf("%s: %s %s not found", u.Source, u.Kind, u.Identifier)
+}
+
+// ResolutionError is returned by ToResolvedCatalog when one or more guideline
+// references could not be resolved.
+type ResolutionError struct {
+	Unresolved []UnresolvedReference
+}
+
+func (r *ResolutionError) Error() string {
+	refs := make([]string, 0, len(r.Unresolved))
+	for _, u := range r.Unresolved {
+		refs = append(refs, u.String())
+	}
+	return fmt.Sprintf("unresolved guideline references: %s", strings.Join(refs, "; "))
+}
+
+// ToResolvedCatalog converts a Layer 1 guidance document into an OSCAL Catalog where
+// shared guidelines are fully resolved. Guidelines referenced through see-also are inlined
+// into the referencing control with their statement, objective and guidance parts, and guidelines
+// listed in the document shared-guidelines are added as controls in a group per referenced
+// document (with a group id of shared_<reference-id>). Shared enhancements are nested under their
+// base control when it is shared as well. Referenced guidelines are looked up in
+// the guidance document itself and in the supplied shared documents by their metadata id.
+//
+// A *normalize.CollisionError is returned when a shared control has the id of a local control or
+// of a control shared from another document. When references cannot be resolved, the catalog is
+// still returned along with a *ResolutionError listing every unresolved reference. Unknown external
+// references are reported the same way with an *UnknownReferenceError, joined with any
+// *ResolutionError.
+func ToResolvedCatalog(guidance layer1.GuidanceDocument, shared []layer1.GuidanceDocument, opts ...Option) (oscalTypes.Catalog, error) {
+	options := applyOptions(opts)
+	catalog, err := ToCatalog(guidance, opts...)
+	var unknownErr *UnknownReferenceError
+	if err != nil && !errors.As(err, &unknownErr) {
+		return oscalTypes.Catalog{}, err
+	}
+
+	local := indexGuidelines(guidance, options.normalizer)
+	sharedIndex := make(map[string]map[string]layer1.Guideline, len(shared))
+	sharedTitles := make(map[string]string, len(shared))
+	for _, doc := range shared {
+		sharedIndex[doc.Metadata.Id] = indexGuidelines(doc, options.references.For(doc.Metadata.Id))
+		sharedTitles[doc.Metadata.Id] = doc.Metadata.Title
+	}
+
+	// lookup returns the referenced guideline and the normalizer for the document it was found in
+	lookup := func(identifier string) (layer1.Guideline, normalize.Normalizer, bool) {
+		if guideline, found := local[options.normalizer.Normalize(identifier)]; found {
+			return guideline, options.normalizer, true
+		}
+		for _, doc := range shared {
+			normalizer := options.references.For(doc.Metadata.Id)
+			if guideline, found := sharedIndex[doc.Metadata.Id][normalizer.Normalize(identifier)]; found {
+				return guideline, normalizer, true
+			}
+		}
+		return layer1.Guideline{}, nil, false
+	}
+
+	var unresolved []UnresolvedReference
+	if catalog.Groups != nil {
+		for i := range *catalog.Groups {
+			group := &(*catalog.Groups)[i]
+			if group.Controls == nil {
+				continue
+			}
+			unresolved = append(unresolved, inlineSeeAlso(*group.Controls, local, lookup)...)
+		}
+	}
+
+	// Shared controls must not reuse the id of a local control or a control shared from another document
+	controlIds := make(map[string]string)
+	var collisions []normalize.Collision
+	if catalog.Groups != nil {
+		for _, group := range *catalog.Groups {
+			collisions = append(collisions, addControlIds(controlIds, group.Controls, guidance.Metadata.Id)...)
+		}
+	}
+
+	var sharedGroups []oscalTypes.Group
+	for _, mapping := range guidance.SharedGuidelines {
+		index, found := sharedIndex[mapping.ReferenceId]
+		normalizer := options.references.For(mapping.ReferenceId)
+		var guidelines []layer1.Guideline
+		selected := make(map[string]bool)
+		for _, identifier := range mapping.Identifiers {
+			guideline, ok := index[normalizer.Normalize(identifier)]
+			if !found || !ok {
+				unresolved = append(unresolved, UnresolvedReference{
+					Source:      guidance.Metadata.Id,
+					ReferenceId: mapping.ReferenceId,
+					Identifier:  identifier,
+					Kind:        ReferenceSharedGuideline,
+				})
+				continue
+			}
+			guidelines = append(guidelines, guideline)
+			selected[normalizer.Normalize(guideline.Id)] = true
+		}
+		if len(guidelines) == 0 {
+			continue
+		}
+		// Enhancements are nested under their base guideline when it is shared as well,
+		// otherwise they are added as top-level controls of the group.
+		for i := range guidelines {
+			if base := guidelines[i].BaseGuidelineID; base != "" && !selected[normalizer.Normalize(base)] {
+				guidelines[i].BaseGuidelineID = ""
+			}
+		}
+
+		groups, err := createControlGroups([]layer1.Category{{
+			// Reference ids are not guaranteed to be valid OSCAL tokens (e.g. 800-53)
+			Id:         fmt.Sprintf("shared_%s", mapping.ReferenceId),
+			Title:      sharedTitles[mapping.ReferenceId],
+			Guidelines: guidelines,
+		}}, nil, normalizer)
+		if err != nil {
+			return oscalTypes.Catalog{}, err
+		}
+		collisions = append(collisions, addControlIds(controlIds, groups[0].Controls, mapping.ReferenceId)...)
+		sharedGroups = append(sharedGroups, groups[0])
+	}
+	if len(collisions) > 0 {
+		return oscalTypes.Catalog{}, &normalize.CollisionError{Scope: guidance.Metadata.Id, Collisions: collisions}
+	}
+
+	if len(sharedGroups) > 0 {
+		if catalog.Groups == nil {
+			catalog.Groups = &[]oscalTypes.Group{}
+		}
+		*catalog.Groups = append(*catalog.Groups, sharedGroups...)
+	}
+
+	if len(unresolved) > 0 {
+		resolutionErr := &ResolutionError{Unresolved: unresolved}
+		if unknownErr != nil {
+			return catalog, errors.Join(unknownErr, resolutionErr)
+		}
+		return catalog, resolutionErr
+	}
+	if unknownErr != nil {
+		return catalog, unknownErr
+	}
+	return catalog, nil
+}
+
+// addControlIds records the ids of the controls and their enhancements with the id of the
+// document they come from and returns a collision for every id that is already recorded.
+func addControlIds(controlIds map[string]string, controls *[]oscalTypes.Control, documentId string) []normalize.Collision {
+	if controls == nil {
+		return nil
+	}
+	var collisions []normalize.Collision
+	for _, control := range *controls {
+		if existing, found := controlIds[control.ID]; found {
+			collisions = append(collisions, normalize.Collision{OSCALId: control.ID, SourceIds: []string{existing, documentId}})
+		} else {
+			controlIds[control.ID] = documentId
+		}
+		collisions = append(collisions, addControlIds(controlIds, control.Controls, documentId)...)
+	}
+	return collisions
+}
+
+// indexGuidelines returns all guidelines in the document keyed by normalized control id.
+func indexGuidelines(guidance layer1.GuidanceDocument, normalizer normalize.Normalizer) map[string]layer1.Guideline {
+	index := make(map[string]layer1.Guideline)
+	for _, category := range guidance.Categories {
+		for _, guideline := range category.Guidelines {
+			index[normalizer.Normalize(guideline.Id)] = guideline
+		}
+	}
+	return index
+}
+
+// inlineSeeAlso adds a see-also part for every resolvable see-also reference on the given controls
+// and their enhancements.
+func inlineSeeAlso(controls []oscalTypes.Control, local map[string]layer1.Guideline, lookup func(string) (layer1.Guideline, normalize.Normalizer, bool)) []UnresolvedReference {
+	var unresolved []UnresolvedReference
+	for i := range controls {
+		control := &controls[i]
+		if control.Controls != nil {
+			unresolved = append(unresolved, inlineSeeAlso(*control.Controls, local, lookup)...)
+		}
+
+		guideline, found := local[control.ID]
+		if !found {
+			continue
+		}
+		for _, also := range guideline.SeeAlso {
+			referenced, normalizer, ok := lookup(also)
+			if !ok {
+				unresolved = append(unresolved, UnresolvedReference{
+					Source:     guideline.Id,
+					Identifier: also,
+					Kind:       ReferenceSeeAlso,
+				})
+				continue
+			}
+			if control.Parts == nil {
+				control.Parts = &[]oscalTypes.Part{}
+			}
+			*control.Parts = append(*control.Parts, inlineGuideline(control.ID, referenced, normalizer))
+		}
+	}
+	return unresolved
+}
+
+// inlineGuideline converts a referenced guideline into a see-also part. Part ids are
+// prefixed with the referencing control id to keep them unique within the catalog.
+func inlineGuideline(controlId string, guideline layer1.Guideline, normalizer normalize.Normalizer) oscalTypes.Part {
+	referenced, _ := guidelineToControl(guideline, nil, normalizer)
+	prefix := fmt.Sprintf("%s_%s", controlId, referenced.ID)
+	part := oscalTypes.Part{
+		Name:  "see-also",
+		ID:    prefix,
+		Title: referenced.Title,
+	}
+	if referenced.Parts != nil {
+		parts := prefixPartIds(controlId, *referenced.Parts)
+		part.Parts = &parts
+	}
+	return part
+}
+
+func prefixPartIds(prefix string, parts []oscalTypes.Part) []oscalTypes.Part {
+	prefixed := make([]oscalTypes.Part, 0, len(parts))
+	for _, part := range parts {
+		if part.ID != "" {
+			part.ID = fmt.Sprintf("%s_%s", prefix, part.ID)
+		}
+		if part.Parts != nil {
+			subParts := prefixPartIds(prefix, *part.Parts)
+			part.Parts = &subParts
+		}
+		prefixed = append(prefixed, part)
+	}
+	return prefixed
+}
@@ -0,0 +1,157 @@
+package controls
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/ossf/gemara/layer1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpower432/gemara2oscal/normalize"
+)
+
+func TestToResolvedCatalog(t *testing.T) {
+	file, err := os.Open("./testdata/800-161.yml")
+	require.NoError(t, err)
+
+	var guidance layer1.GuidanceDocument
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&guidance)
+	require.NoError(t, err)
+
+	shared := layer1.GuidanceDocument{
+		Metadata: layer1.Metadata{
+			Id:    "800-53",
+			Title: "NIST SP 800-53r5",
+		},
+		Categories: []layer1.Category{
+			{
+				Id:    "RA",
+				Title: "Risk Assessment",
+				Guidelines: []layer1.Guideline{
+					{
+						Id:              "RA-9",
+						Title:           "Criticality Analysis",
+						Objective:       "Identify critical system components and functions.",
+						Recommendations: []string{"Perform a criticality analysis."},
+					},
+					{
+						Id:              "RA-3(1)",
+						Title:           "Supply Chain Risk Assessment",
+						BaseGuidelineID: "RA-3",
+					},
+					{
+						Id:    "RA-3",
+						Title: "Risk Assessment",
+					},
+					{
+						Id:    "AC-5",
+						Title: "Separation of Duties",
+					},
+				},
+			},
+		},
+	}
+	guidance.SharedGuidelines = []layer1.Mapping{
+		{
+			ReferenceId: "800-53",
+			Identifiers: []string{"RA-3(1)", "RA-3", "RA-99"},
+		},
+	}
+
+	catalog, err := ToResolvedCatalog(guidance, []layer1.GuidanceDocument{shared})
+	var resolutionErr *ResolutionError
+	require.True(t, errors.As(err, &resolutionErr))
+	// SA-15 enhancements are not defined in either document
+	require.Len(t, resolutionErr.Unresolved, 6)
+	require.Equal(t, UnresolvedReference{
+		Source:      "NIST-SP-800-161r1-custom",
+		ReferenceId: "800-53",
+		Identifier:  "RA-99",
+		Kind:        ReferenceSharedGuideline,
+	}, resolutionErr.Unresolved[5])
+
+	groups := *catalog.Groups
+	require.Len(t, groups, 6)
+	require.Equal(t, "shared_800-53", groups[5].ID)
+	require.Len(t, *groups[5].Controls, 1)
+	ra3 := (*groups[5].Controls)[0]
+	require.Equal(t, "ra-3", ra3.ID)
+	require.Len(t, *ra3.Controls, 1)
+	require.Equal(t, "ra-3.1", (*ra3.Controls)[0].ID)
+
+	sr := (*groups[4].Controls)[0]
+	require.Equal(t, "sr-3", sr.ID)
+	seeAlso := (*sr.Parts)[len(*sr.Parts)-1]
+	require.Equal(t, "see-also", seeAlso.Name)
+	require.Equal(t, "sr-3_ra-9", seeAlso.ID)
+	require.Len(t, *seeAlso.Parts, 3)
+
+	oscalModels := oscalTypes.OscalModels{
+		Catalog: &catalog,
+	}
+
+	validator := validation.NewSchemaValidator()
+	err = validator.Validate(oscalModels)
+	require.NoError(t, err)
+}
+
+func TestToResolvedCatalog_UnknownReference(t *testing.T) {
+	file, err := os.Open("./testdata/800-161.yml")
+	require.NoError(t, err)
+
+	var guidance layer1.GuidanceDocument
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&guidance)
+	require.NoError(t, err)
+
+	guidance.Categories[0].Guidelines[0].ExternalReferences = append(
+		guidance.Categories[0].Guidelines[0].ExternalReferences,
+		"unknown-resource",
+	)
+
+	// The resolved catalog is returned with both the dropped and the unresolved references
+	catalog, err := ToResolvedCatalog(guidance, nil)
+	var unknownErr *UnknownReferenceError
+	require.ErrorAs(t, err, &unknownErr)
+	require.Len(t, unknownErr.Diagnostics, 1)
+	require.Equal(t, SeverityError, unknownErr.Diagnostics[0].Severity)
+	require.Equal(t, "ac-5", unknownErr.Diagnostics[0].Location)
+	var resolutionErr *ResolutionError
+	require.ErrorAs(t, err, &resolutionErr)
+
+	require.NotNil(t, catalog.Groups)
+	require.Len(t, *catalog.Groups, 5)
+	require.Equal(t, "ac-5", (*(*catalog.Groups)[0].Controls)[0].ID)
+}
+
+func TestToResolvedCatalog_Collisions(t *testing.T) {
+	file, err := os.Open("./testdata/800-161.yml")
+	require.NoError(t, err)
+
+	var guidance layer1.GuidanceDocument
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&guidance)
+	require.NoError(t, err)
+
+	shared := layer1.GuidanceDocument{
+		Metadata: layer1.Metadata{Id: "800-53", Title: "NIST SP 800-53r5"},
+		Categories: []layer1.Category{
+			{
+				Id:         "AC",
+				Title:      "Access Control",
+				Guidelines: []layer1.Guideline{{Id: "AC-5", Title: "Separation of Duties"}},
+			},
+		},
+	}
+	guidance.SharedGuidelines = []layer1.Mapping{{ReferenceId: "800-53", Identifiers: []string{"AC-5"}}}
+
+	_, err = ToResolvedCatalog(guidance, []layer1.GuidanceDocument{shared})
+	var collisionErr *normalize.CollisionError
+	require.ErrorAs(t, err, &collisionErr)
+	require.Equal(t, []normalize.Collision{{OSCALId: "ac-5", SourceIds: []string{"NIST-SP-800-161r1-custom", "800-53"}}}, collisionErr.Collisions)
+}
@@ -0,0 +1,168 @@
+package controls
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/ossf/gemara/layer1"
+)
+
+// ErrRemoteResource is returned by a ResourceLoader for resources it does not load (e.g. web pages).
+// Remote resources are linked, but not hashed or embedded.
+var ErrRemoteResource = errors.New("remote resource")
+
+// ResourceLoader returns the content of the resource at href.
+type ResourceLoader func(href string) ([]byte, error)
+
+// LocalResources returns a ResourceLoader that reads local files, resolving relative paths
+// against dir. Hrefs with a URL scheme other than file return ErrRemoteResource.
+func LocalResources(dir string) ResourceLoader {
+	return func(href string) ([]byte, error) {
+		location, err := url.Parse(href)
+		if err != nil {
+			return nil, err
+		}
+		filePath := href
+		switch location.Scheme {
+		case "":
+		case "file":
+			filePath = location.Path
+		default:
+			return nil, ErrRemoteResource
+		}
+		if !filepath.IsAbs(filePath) {
+			filePath = filepath.Join(dir, filePath)
+		}
+		return os.ReadFile(filePath)
+	}
+}
+
+// mediaTypes covers extensions that are commonly used for references, but are not
+// registered with the mime package on all platforms.
+var mediaTypes = map[string]string{
+	".md":   "text/markdown",
+	".yaml": "application/yaml",
+	".yml":  "application/yaml",
+	".json": "application/json",
+	".pdf":  "application/pdf",
+}
+
+// inferMediaType returns the media type from the href extension, falling back to
+// content sniffing when the content is available.
+func inferMediaType(href string, content []byte) string {
+	hrefPath := href
+	if location, err := url.Parse(href); err == nil {
+		hrefPath = location.Path
+	}
+	ext := strings.ToLower(path.Ext(hrefPath))
+	if mediaType, found := mediaTypes[ext]; found {
+		return mediaType
+	}
+	if ext != "" {
+		if mediaType := mime.TypeByExtension(ext); mediaType != "" {
+			return stripMediaTypeParams(mediaType)
+		}
+	}
+	if content != nil {
+		return stripMediaTypeParams(http.DetectContentType(content))
+	}
+	return ""
+}
+
+func stripMediaTypeParams(mediaType string) string {
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	return strings.TrimSpace(mediaType)
+}
+
+// resourcesToBackMatter converts resource references into back-matter resources. Reference fields
+// are recorded as props so they can be converted back to Layer 1. When requested, local resource
+// content is hashed (SHA-256) and embedded as base64.
+func resourcesToBackMatter(documentId string, resourceRefs []layer1.ResourceReference, options options) (*oscalTypes.BackMatter, error) {
+	uuids := options.UUIDs()
+	var resources []oscalTypes.Resource
+	for _, ref := range resourceRefs {
+		// The id prop must be first, it is used to link controls to resources
+		props := []oscalTypes.Property{options.vocabulary.Prop("id", ref.Id)}
+		if ref.IssuingBody != "" {
+			props = append(props, options.vocabulary.Prop("issuing-body", ref.IssuingBody))
+		}
+		if ref.PublicationDate != "" {
+			props = append(props, options.vocabulary.Prop("publication-date", ref.PublicationDate))
+		}
+
+		resource := oscalTypes.Resource{
+			UUID:        uuids.Generate(documentId, "resource", ref.Id),
+			Title:       ref.Title,
+			Description: ref.Description,
+			Props:       &props,
+		}
+
+		if ref.Url != "" {
+			content, err := options.loadResource(ref.Url)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load resource %s: %w", ref.Id, err)
+			}
+
+			rlink := oscalTypes.ResourceLink{
+				Href:      ref.Url,
+				MediaType: inferMediaType(ref.Url, content),
+			}
+			if options.resourceHashes && content != nil {
+				sum := sha256.Sum256(content)
+				rlink.Hashes = &[]oscalTypes.Hash{
+					{
+						Algorithm: "SHA-256",
+						Value:     hex.EncodeToString(sum[:]),
+					},
+				}
+			}
+			resource.Rlinks = &[]oscalTypes.ResourceLink{rlink}
+
+			if options.embedResources && content != nil {
+				resource.Base64 = &oscalTypes.Base64{
+					Filename:  path.Base(ref.Url),
+					MediaType: rlink.MediaType,
+					Value:     base64.StdEncoding.EncodeToString(content),
+				}
+			}
+		}
+		resources = append(resources, resource)
+	}
+
+	if len(resources) == 0 {
+		return nil, nil
+	}
+
+	backmatter := oscalTypes.BackMatter{
+		Resources: &resources,
+	}
+	return &backmatter, nil
+}
+
+// loadResource returns the resource content when it is needed for hashing or embedding. Remote
+// resources return no content.
+func (o options) loadResource(href string) ([]byte, error) {
+	if !o.resourceHashes && !o.embedResources {
+		return nil, nil
+	}
+	loader := o.resourceLoader
+	if loader == nil {
+		loader = LocalResources("")
+	}
+	content, err := loader(href)
+	if errors.Is(err, ErrRemoteResource) {
+		return nil, nil
+	}
+	return content, err
+}
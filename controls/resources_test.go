@@ -0,0 +1,97 @@
+package controls
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/ossf/gemara/layer1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToCatalog_Resources(t *testing.T) {
+	file, err := os.Open("./testdata/800-161.yml")
+	require.NoError(t, err)
+
+	var guidance layer1.GuidanceDocument
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&guidance)
+	require.NoError(t, err)
+
+	guidance.Metadata.Resources = append(guidance.Metadata.Resources, layer1.ResourceReference{
+		Id:          "scs-policy",
+		Title:       "Supply Chain Security Policy",
+		Description: "Internal supply chain security policy.",
+		Url:         "supply-chain-policy.md",
+	})
+
+	catalog, err := ToCatalog(guidance,
+		WithResourceLoader(LocalResources("./testdata")),
+		WithResourceHashes(),
+		WithEmbeddedResources(),
+	)
+	require.NoError(t, err)
+
+	resources := *catalog.BackMatter.Resources
+	require.Len(t, resources, 2)
+
+	// Remote resources are linked without hashes or content
+	nist := resources[0]
+	require.Equal(t, []oscalTypes.Property{
+		{Name: "id", Value: "nist-sp-800-161r1", Ns: extensions.TrestleNameSpace},
+		{Name: "issuing-body", Value: "National Institute of Standards and Technology", Ns: extensions.TrestleNameSpace},
+		{Name: "publication-date", Value: "2022-05", Ns: extensions.TrestleNameSpace},
+	}, *nist.Props)
+	require.Nil(t, nist.Citation)
+	require.Nil(t, nist.Base64)
+	require.Nil(t, (*nist.Rlinks)[0].Hashes)
+
+	content, err := os.ReadFile("./testdata/supply-chain-policy.md")
+	require.NoError(t, err)
+	sum := sha256.Sum256(content)
+
+	policy := resources[1]
+	rlink := (*policy.Rlinks)[0]
+	require.Equal(t, "text/markdown", rlink.MediaType)
+	require.Equal(t, []oscalTypes.Hash{{Algorithm: "SHA-256", Value: hex.EncodeToString(sum[:])}}, *rlink.Hashes)
+	require.Equal(t, "supply-chain-policy.md", policy.Base64.Filename)
+	decoded, err := base64.StdEncoding.DecodeString(policy.Base64.Value)
+	require.NoError(t, err)
+	require.Equal(t, content, decoded)
+
+	validator := validation.NewSchemaValidator()
+	err = validator.Validate(oscalTypes.OscalModels{Catalog: &catalog})
+	require.NoError(t, err)
+
+	got, _ := FromCatalog(catalog)
+	require.Equal(t, guidance.Metadata.Resources[0], got.Metadata.Resources[0])
+
+	guidance.Metadata.Resources[1].Url = "missing.md"
+	_, err = ToCatalog(guidance, WithResourceLoader(LocalResources("./testdata")), WithResourceHashes())
+	require.ErrorContains(t, err, "failed to load resource scs-policy")
+}
+
+func TestInferMediaType(t *testing.T) {
+	tests := []struct {
+		name    string
+		href    string
+		content []byte
+		want    string
+	}{
+		{name: "PDF", href: "policies/access.pdf", want: "application/pdf"},
+		{name: "Markdown URL", href: "https://example.com/policy.md?raw=true", want: "text/markdown"},
+		{name: "Web page", href: "https://csrc.nist.gov/pubs/sp/800/161/r1/upd1/final", want: ""},
+		{name: "Sniffed", href: "policy", content: []byte("plain text policy"), want: "text/plain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, inferMediaType(tt.href, tt.content))
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package controls
+
+import (
+	"fmt"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/ossf/gemara/layer1"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+	"github.com/jpower432/gemara2oscal/normalize"
+)
+
+// OrphanedEnhancement is a guideline whose base guideline cannot be found.
+type OrphanedEnhancement struct {
+	GuidelineId     string
+	BaseGuidelineId string
+}
+
+// OrphanedEnhancementError is returned when one or more control enhancements
+// cannot be attached to their base guideline.
+type OrphanedEnhancementError struct {
+	Orphans []OrphanedEnhancement
+}
+
+func (o *OrphanedEnhancementError) Error() string {
+	orphans := make([]string, 0, len(o.Orphans))
+	for _, orphan := range o.Orphans {
+		orphans = append(orphans, fmt.Sprintf("%s (base %s)", orphan.GuidelineId, orphan.BaseGuidelineId))
+	}
+	return fmt.Sprintf("orphaned control enhancements: %s", strings.Join(orphans, ", "))
+}
+
+type controlNode struct {
+	control     oscalTypes.Control
+	guidelineId string
+	baseId      string
+	baseRawId   string
+	category    string
+	children    []string
+}
+
+// createControlGroups creates a group per category and nests control enhancements
+// under their base control. Enhancements may appear before their base control, reference a
+// base control in another category and be nested to any depth.
+func createControlGroups(categories []layer1.Category, resourcesMap map[string]string, normalizer normalize.Normalizer) ([]oscalTypes.Group, error) {
+	nodes := make(map[string]*controlNode)
+	// Track controls in document order to keep the output stable
+	var order []string
+	for _, category := range categories {
+		for _, guideline := range category.Guidelines {
+			control, parent := guidelineToControl(guideline, resourcesMap, normalizer)
+			if _, exists := nodes[control.ID]; !exists {
+				order = append(order, control.ID)
+			}
+			nodes[control.ID] = &controlNode{
+				control:     control,
+				guidelineId: guideline.Id,
+				baseId:      parent,
+				baseRawId:   guideline.BaseGuidelineID,
+				category:    category.Id,
+			}
+		}
+	}
+
+	var orphans []OrphanedEnhancement
+	for _, id := range order {
+		node := nodes[id]
+		if node.baseId == "" {
+			continue
+		}
+		parent, found := nodes[node.baseId]
+		if !found {
+			orphans = append(orphans, OrphanedEnhancement{GuidelineId: node.guidelineId, BaseGuidelineId: node.baseRawId})
+			continue
+		}
+		parent.children = append(parent.children, id)
+	}
+
+	roots := make(map[string][]oscalTypes.Control)
+	attached := make(map[string]bool, len(nodes))
+	for _, id := range order {
+		node := nodes[id]
+		if node.baseId != "" {
+			continue
+		}
+		roots[node.category] = append(roots[node.category], assembleControl(id, nodes, attached))
+	}
+
+	// Enhancements with an existing base that were never attached are part of a cycle
+	for _, id := range order {
+		node := nodes[id]
+		if node.baseId == "" || attached[id] {
+			continue
+		}
+		if _, found := nodes[node.baseId]; found {
+			orphans = append(orphans, OrphanedEnhancement{GuidelineId: node.guidelineId, BaseGuidelineId: node.baseRawId})
+		}
+	}
+
+	if len(orphans) > 0 {
+		return nil, &OrphanedEnhancementError{Orphans: orphans}
+	}
+
+	groups := make([]oscalTypes.Group, 0, len(categories))
+	for _, category := range categories {
+		controls := roots[category.Id]
+		groups = append(groups, oscalTypes.Group{
+			ID:       category.Id,
+			Title:    category.Title,
+			Controls: utils.NilIfEmpty(&controls),
+		})
+	}
+	return groups, nil
+}
+
+func assembleControl(id string, nodes map[string]*controlNode, attached map[string]bool) oscalTypes.Control {
+	attached[id] = true
+	node := nodes[id]
+	control := node.control
+
+	var children []oscalTypes.Control
+	for _, childId := range node.children {
+		if attached[childId] {
+			continue
+		}
+		children = append(children, assembleControl(childId, nodes, attached))
+	}
+	control.Controls = utils.NilIfEmpty(&children)
+	return control
+}
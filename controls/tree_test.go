@@ -0,0 +1,149 @@
+package controls
+
+import (
+	"errors"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/models"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/ossf/gemara/layer1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpower432/gemara2oscal/normalize"
+)
+
+func TestCreateControlGroups(t *testing.T) {
+	tests := []struct {
+		name        string
+		categories  []layer1.Category
+		wantTree    map[string][]string
+		wantOrphans []OrphanedEnhancement
+	}{
+		{
+			name: "Success/ChildBeforeParent",
+			categories: []layer1.Category{
+				{
+					Id: "AU",
+					Guidelines: []layer1.Guideline{
+						{Id: "AU-6(9)", BaseGuidelineID: "AU-6"},
+						{Id: "AU-6"},
+					},
+				},
+			},
+			wantTree: map[string][]string{
+				"AU": {"au-6", "au-6/au-6.9"},
+			},
+		},
+		{
+			name: "Success/MultiLevel",
+			categories: []layer1.Category{
+				{
+					Id: "AC",
+					Guidelines: []layer1.Guideline{
+						{Id: "AC-2(1)(2)", BaseGuidelineID: "AC-2(1)"},
+						{Id: "AC-2"},
+						{Id: "AC-2(1)", BaseGuidelineID: "AC-2"},
+					},
+				},
+			},
+			wantTree: map[string][]string{
+				"AC": {"ac-2", "ac-2/ac-2.1", "ac-2/ac-2.1/ac-2.1.2"},
+			},
+		},
+		{
+			name: "Success/CrossCategory",
+			categories: []layer1.Category{
+				{
+					Id: "SR",
+					Guidelines: []layer1.Guideline{
+						{Id: "SA-15(1)", BaseGuidelineID: "SA-15"},
+					},
+				},
+				{
+					Id: "SA",
+					Guidelines: []layer1.Guideline{
+						{Id: "SA-15"},
+					},
+				},
+			},
+			wantTree: map[string][]string{
+				"SR": nil,
+				"SA": {"sa-15", "sa-15/sa-15.1"},
+			},
+		},
+		{
+			name: "Failure/Orphaned",
+			categories: []layer1.Category{
+				{
+					Id: "AU",
+					Guidelines: []layer1.Guideline{
+						{Id: "AU-6(9)", BaseGuidelineID: "AU-6"},
+						{Id: "AU-7"},
+					},
+				},
+			},
+			wantOrphans: []OrphanedEnhancement{
+				{GuidelineId: "AU-6(9)", BaseGuidelineId: "AU-6"},
+			},
+		},
+		{
+			name: "Failure/Cycle",
+			categories: []layer1.Category{
+				{
+					Id: "AU",
+					Guidelines: []layer1.Guideline{
+						{Id: "AU-6(1)", BaseGuidelineID: "AU-6(2)"},
+						{Id: "AU-6(2)", BaseGuidelineID: "AU-6(1)"},
+					},
+				},
+			},
+			wantOrphans: []OrphanedEnhancement{
+				{GuidelineId: "AU-6(1)", BaseGuidelineId: "AU-6(2)"},
+				{GuidelineId: "AU-6(2)", BaseGuidelineId: "AU-6(1)"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, err := createControlGroups(tt.categories, nil, normalize.NIST80053)
+			if tt.wantOrphans != nil {
+				var orphanErr *OrphanedEnhancementError
+				require.True(t, errors.As(err, &orphanErr))
+				require.Equal(t, tt.wantOrphans, orphanErr.Orphans)
+				return
+			}
+			require.NoError(t, err)
+
+			gotTree := make(map[string][]string)
+			for _, group := range groups {
+				gotTree[group.ID] = nil
+				if group.Controls != nil {
+					gotTree[group.ID] = controlPaths("", *group.Controls)
+				}
+			}
+			require.Equal(t, tt.wantTree, gotTree)
+
+			catalog := oscalTypes.Catalog{
+				UUID:     "0b8a6a2e-5f4c-4c3a-9f43-6f1f3c1d2e7a",
+				Metadata: models.NewSampleMetadata(),
+				Groups:   &groups,
+			}
+			err = validation.NewSchemaValidator().Validate(oscalTypes.OscalModels{Catalog: &catalog})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func controlPaths(prefix string, controls []oscalTypes.Control) []string {
+	var paths []string
+	for _, control := range controls {
+		path := prefix + control.ID
+		paths = append(paths, path)
+		if control.Controls != nil {
+			paths = append(paths, controlPaths(path+"/", *control.Controls)...)
+		}
+	}
+	return paths
+}
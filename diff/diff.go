@@ -0,0 +1,147 @@
+package diff
+
+import (
+	"sort"
+	"strings"
+)
+
+// element is a comparable element of a document with its fields in a fixed order.
+type element struct {
+	fieldNames []string
+	fields     map[string]string
+}
+
+func newElement() *element {
+	return &element{fields: make(map[string]string)}
+}
+
+func (e *element) set(name, value string) *element {
+	if _, exists := e.fields[name]; !exists {
+		e.fieldNames = append(e.fieldNames, name)
+	}
+	e.fields[name] = strings.TrimSpace(value)
+	return e
+}
+
+// fingerprint identifies an element by its content, used to detect renames.
+func (e *element) fingerprint() string {
+	values := make([]string, 0, len(e.fieldNames))
+	for _, name := range e.fieldNames {
+		values = append(values, e.fields[name])
+	}
+	return strings.Join(values, "\x00")
+}
+
+// snapshot is a flattened view of a document keyed by kind and element id.
+type snapshot struct {
+	elements map[Kind]map[string]*element
+	order    map[Kind][]string
+}
+
+func newSnapshot() *snapshot {
+	return &snapshot{
+		elements: make(map[Kind]map[string]*element),
+		order:    make(map[Kind][]string),
+	}
+}
+
+func (s *snapshot) add(kind Kind, id string) *element {
+	if s.elements[kind] == nil {
+		s.elements[kind] = make(map[string]*element)
+	}
+	if existing, found := s.elements[kind][id]; found {
+		return existing
+	}
+	e := newElement()
+	s.elements[kind][id] = e
+	s.order[kind] = append(s.order[kind], id)
+	return e
+}
+
+// compare returns all changes between the old and new snapshots.
+func compare(old, new *snapshot) []Change {
+	var changes []Change
+	for _, kind := range kindOrder {
+		oldElements, newElements := old.elements[kind], new.elements[kind]
+
+		var removed, added []string
+		for _, id := range old.order[kind] {
+			if _, found := newElements[id]; !found {
+				removed = append(removed, id)
+			}
+		}
+		for _, id := range new.order[kind] {
+			if _, found := oldElements[id]; !found {
+				added = append(added, id)
+			}
+		}
+
+		// Elements removed and added with identical non-empty content are renames
+		renamedFrom := make(map[string]string)
+		removedByContent := make(map[string]string)
+		for _, id := range removed {
+			if fingerprint := oldElements[id].fingerprint(); strings.Trim(fingerprint, "\x00") != "" {
+				removedByContent[fingerprint] = id
+			}
+		}
+		for _, id := range added {
+			if previous, found := removedByContent[newElements[id].fingerprint()]; found {
+				renamedFrom[id] = previous
+				delete(removedByContent, newElements[id].fingerprint())
+			}
+		}
+		renamedTo := make(map[string]bool, len(renamedFrom))
+		for _, previous := range renamedFrom {
+			renamedTo[previous] = true
+		}
+
+		var kindChanges []Change
+		for _, id := range removed {
+			if !renamedTo[id] {
+				kindChanges = append(kindChanges, Change{Kind: kind, Type: Removed, Id: id})
+			}
+		}
+		for _, id := range added {
+			if previous, ok := renamedFrom[id]; ok {
+				kindChanges = append(kindChanges, Change{Kind: kind, Type: Renamed, Id: id, PreviousId: previous})
+				continue
+			}
+			kindChanges = append(kindChanges, Change{Kind: kind, Type: Added, Id: id})
+		}
+		for _, id := range new.order[kind] {
+			oldElement, found := oldElements[id]
+			if !found {
+				continue
+			}
+			newElement := newElements[id]
+			for _, field := range fieldUnion(oldElement, newElement) {
+				if oldElement.fields[field] != newElement.fields[field] {
+					kindChanges = append(kindChanges, Change{
+						Kind:  kind,
+						Type:  Modified,
+						Id:    id,
+						Field: field,
+						Old:   oldElement.fields[field],
+						New:   newElement.fields[field],
+					})
+				}
+			}
+		}
+
+		sort.SliceStable(kindChanges, func(i, j int) bool {
+			return kindChanges[i].Id < kindChanges[j].Id
+		})
+		changes = append(changes, kindChanges...)
+	}
+	return changes
+}
+
+func fieldUnion(a, b *element) []string {
+	names := append([]string{}, a.fieldNames...)
+	for _, name := range b.fieldNames {
+		if _, found := a.fields[name]; !found {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,124 @@
+package diff
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/ossf/gemara/layer1"
+	"github.com/ossf/gemara/layer2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpower432/gemara2oscal/controls"
+)
+
+func loadCatalog(t *testing.T) layer2.Catalog {
+	file, err := os.Open("../component/testdata/good-osps.yml")
+	require.NoError(t, err)
+
+	var catalog layer2.Catalog
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&catalog)
+	require.NoError(t, err)
+	return catalog
+}
+
+func TestCatalogs(t *testing.T) {
+	old := loadCatalog(t)
+	new := loadCatalog(t)
+	new.Metadata.Version = "2025.02"
+
+	control := &new.ControlFamilies[0].Controls[0]
+	control.Objective = "Require non-author approval."
+	control.AssessmentRequirements[0].Id = "OSPS-QA-07.02"
+	control.AssessmentRequirements[0].RecommendedParameters[0].Default = 2
+	control.GuidelineMappings = control.GuidelineMappings[1:]
+	new.ControlFamilies[0].Controls = append(new.ControlFamilies[0].Controls, layer2.Control{
+		Id:    "OSPS-QA-08",
+		Title: "New control",
+	})
+
+	report := Catalogs(old, new)
+	require.Equal(t, "2025.02", report.ToVersion)
+	require.Equal(t, []Change{
+		{
+			Kind:  KindControl,
+			Type:  Modified,
+			Id:    "OSPS-QA-07",
+			Field: "objective",
+			Old:   "Ensure that the project's version control system requires at least one\nnon-author approval of changes before merging into the release or primary\nbranch.",
+			New:   "Require non-author approval.",
+		},
+		{Kind: KindControl, Type: Added, Id: "OSPS-QA-08"},
+		{Kind: KindAssessmentRequirement, Type: Renamed, Id: "OSPS-QA-07.02", PreviousId: "OSPS-QA-07.01"},
+		{Kind: KindParameter, Type: Modified, Id: "main_branch_min_approvals", Field: "default", Old: "1", New: "2"},
+		{Kind: KindMapping, Type: Removed, Id: "OSPS-QA-07 -> BPB/B-G-3"},
+	}, report.Changes)
+
+	data, err := report.JSON()
+	require.NoError(t, err)
+	var decoded Report
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, report, decoded)
+
+	markdown := report.Markdown()
+	require.Contains(t, markdown, "## Assessment Requirements\n\n- **renamed** `OSPS-QA-07.01` → `OSPS-QA-07.02`\n")
+	require.Contains(t, markdown, "  - new: 2\n")
+
+	require.False(t, Catalogs(old, old).HasChanges())
+	require.Contains(t, Catalogs(old, old).Markdown(), "No changes.")
+}
+
+func TestGuidanceDocumentsAndOSCALCatalogs(t *testing.T) {
+	old := layer1.GuidanceDocument{
+		Metadata: layer1.Metadata{
+			Id:              "guidance",
+			Title:           "Guidance",
+			PublicationDate: "2025-01-01",
+			LastModified:    "2025-01-01 00:00:00",
+			Resources: []layer1.ResourceReference{
+				{Id: "SP-800-53", Title: "NIST SP 800-53", Url: "https://csrc.nist.gov/pubs/sp/800/53/r5/upd1/final"},
+				{Id: "SSDF", Title: "NIST SP 800-218", Url: "https://csrc.nist.gov/pubs/sp/800/218/final"},
+			},
+		},
+		Categories: []layer1.Category{
+			{
+				Id: "AC",
+				Guidelines: []layer1.Guideline{
+					{Id: "AC-5", Title: "Separation of Duties", Objective: "Separate duties.", ExternalReferences: []string{"SP-800-53"}},
+					{Id: "AC-6", Title: "Least Privilege"},
+				},
+			},
+		},
+	}
+	new := old
+	new.Categories = []layer1.Category{
+		{
+			Id: "AC",
+			Guidelines: []layer1.Guideline{
+				{Id: "AC-5", Title: "Separation of Duties", Objective: "Separate duties for releases.", ExternalReferences: []string{"SP-800-53", "SSDF"}},
+			},
+		},
+	}
+
+	report := GuidanceDocuments(old, new)
+	require.Equal(t, []Change{
+		{Kind: KindControl, Type: Modified, Id: "AC-5", Field: "objective", Old: "Separate duties.", New: "Separate duties for releases."},
+		{Kind: KindControl, Type: Removed, Id: "AC-6"},
+	}, report.Changes)
+
+	oldCatalog, err := controls.ToCatalog(old)
+	require.NoError(t, err)
+	newCatalog, err := controls.ToCatalog(new)
+	require.NoError(t, err)
+
+	// Resource links are compared by resource id, not by the regenerated resource UUIDs
+	report = OSCALCatalogs(oldCatalog, newCatalog)
+	require.Equal(t, []Change{
+		{Kind: KindControl, Type: Modified, Id: "ac-5", Field: "part ac-5_obj", Old: "Separate duties.", New: "Separate duties for releases."},
+		{Kind: KindControl, Type: Removed, Id: "ac-6"},
+		{Kind: KindLink, Type: Added, Id: "ac-5 -> #SSDF"},
+	}, report.Changes)
+	require.Contains(t, report.Markdown(), "## Links\n\n- **added** `ac-5 -> #SSDF`\n")
+}
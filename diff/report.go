@@ -0,0 +1,134 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Kind is the type of element that changed.
+type Kind string
+
+const (
+	KindControl               Kind = "control"
+	KindAssessmentRequirement Kind = "assessment-requirement"
+	KindParameter             Kind = "parameter"
+	KindMapping               Kind = "mapping"
+	// KindLink is a link of an OSCAL control to another control or a back-matter resource.
+	KindLink Kind = "link"
+)
+
+// kindOrder defines the order of kinds in a report.
+var kindOrder = []Kind{KindControl, KindAssessmentRequirement, KindParameter, KindMapping, KindLink}
+
+// ChangeType describes how an element changed. Renamed is only reported when a removed and an
+// added element of the same kind have identical, non-empty content. Renames with any other edit
+// are reported as a removal and an addition.
+type ChangeType string
+
+const (
+	Added    ChangeType = "added"
+	Removed  ChangeType = "removed"
+	Renamed  ChangeType = "renamed"
+	Modified ChangeType = "modified"
+)
+
+// Change is a single difference between two versions of a document.
+type Change struct {
+	Kind Kind       `json:"kind"`
+	Type ChangeType `json:"type"`
+	Id   string     `json:"id"`
+	// PreviousId is set for renamed elements.
+	PreviousId string `json:"previous-id,omitempty"`
+	// Field is set for modified elements.
+	Field string `json:"field,omitempty"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// Report lists all changes between two versions of a document.
+type Report struct {
+	Title       string   `json:"title"`
+	FromVersion string   `json:"from-version,omitempty"`
+	ToVersion   string   `json:"to-version,omitempty"`
+	Changes     []Change `json:"changes"`
+}
+
+// HasChanges returns true if the report contains any changes.
+func (r Report) HasChanges() bool {
+	return len(r.Changes) > 0
+}
+
+// JSON returns the report encoded as indented JSON.
+func (r Report) JSON() ([]byte, error) {
+	if r.Changes == nil {
+		r.Changes = []Change{}
+	}
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Markdown returns the report rendered as Markdown for review in pull requests.
+func (r Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Change Report: %s\n\n", r.Title)
+	if r.FromVersion != "" || r.ToVersion != "" {
+		fmt.Fprintf(&b, "Version `%s` → `%s`\n\n", r.FromVersion, r.ToVersion)
+	}
+
+	if !r.HasChanges() {
+		b.WriteString("No changes.\n")
+		return b.String()
+	}
+
+	for _, kind := range kindOrder {
+		var changes []Change
+		for _, change := range r.Changes {
+			if change.Kind == kind {
+				changes = append(changes, change)
+			}
+		}
+		if len(changes) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "## %s\n\n", kindHeading(kind))
+		for _, change := range changes {
+			switch change.Type {
+			case Renamed:
+				fmt.Fprintf(&b, "- **%s** `%s` → `%s`\n", change.Type, change.PreviousId, change.Id)
+			case Modified:
+				fmt.Fprintf(&b, "- **%s** `%s` (%s)\n", change.Type, change.Id, change.Field)
+				fmt.Fprintf(&b, "  - old: %s\n", markdownLine(change.Old))
+				fmt.Fprintf(&b, "  - new: %s\n", markdownLine(change.New))
+			default:
+				fmt.Fprintf(&b, "- **%s** `%s`\n", change.Type, change.Id)
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func kindHeading(kind Kind) string {
+	switch kind {
+	case KindControl:
+		return "Controls"
+	case KindAssessmentRequirement:
+		return "Assessment Requirements"
+	case KindParameter:
+		return "Parameters"
+	case KindMapping:
+		return "Mappings"
+	case KindLink:
+		return "Links"
+	default:
+		return string(kind)
+	}
+}
+
+func markdownLine(text string) string {
+	if text == "" {
+		return "_(empty)_"
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,215 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/ossf/gemara/layer1"
+	"github.com/ossf/gemara/layer2"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+)
+
+// GuidanceDocuments compares two versions of a Layer 1 guidance document. Guidelines are
+// reported as controls and guideline mappings as mappings.
+func GuidanceDocuments(old, new layer1.GuidanceDocument) Report {
+	return Report{
+		Title:       new.Metadata.Title,
+		FromVersion: old.Metadata.Version,
+		ToVersion:   new.Metadata.Version,
+		Changes:     compare(guidanceSnapshot(old), guidanceSnapshot(new)),
+	}
+}
+
+// Catalogs compares two versions of a Layer 2 catalog.
+func Catalogs(old, new layer2.Catalog) Report {
+	return Report{
+		Title:       new.Metadata.Title,
+		FromVersion: old.Metadata.Version,
+		ToVersion:   new.Metadata.Version,
+		Changes:     compare(catalogSnapshot(old), catalogSnapshot(new)),
+	}
+}
+
+// OSCALCatalogs compares two versions of an OSCAL catalog, such as the results of
+// converting two versions of the same Gemara document. Links to back-matter resources are
+// keyed by the resource id prop, since resource UUIDs are regenerated on every conversion.
+func OSCALCatalogs(old, new oscalTypes.Catalog) Report {
+	return Report{
+		Title:       new.Metadata.Title,
+		FromVersion: old.Metadata.Version,
+		ToVersion:   new.Metadata.Version,
+		Changes:     compare(oscalSnapshot(old), oscalSnapshot(new)),
+	}
+}
+
+func guidanceSnapshot(guidance layer1.GuidanceDocument) *snapshot {
+	s := newSnapshot()
+	for _, category := range guidance.Categories {
+		for _, guideline := range category.Guidelines {
+			control := s.add(KindControl, guideline.Id).
+				set("title", guideline.Title).
+				set("objective", guideline.Objective).
+				set("recommendations", strings.Join(guideline.Recommendations, "\n")).
+				set("base-guideline-id", guideline.BaseGuidelineID)
+			for _, part := range guideline.GuidelineParts {
+				control.set(fmt.Sprintf("part %s", part.Id), part.Prose)
+			}
+			addMappings(s, guideline.Id, guidelineMappings(guideline.GuidelineMappings))
+		}
+	}
+	return s
+}
+
+func catalogSnapshot(catalog layer2.Catalog) *snapshot {
+	s := newSnapshot()
+	for _, family := range catalog.ControlFamilies {
+		for _, control := range family.Controls {
+			s.add(KindControl, control.Id).
+				set("title", control.Title).
+				set("objective", control.Objective)
+
+			for _, requirement := range control.AssessmentRequirements {
+				s.add(KindAssessmentRequirement, requirement.Id).
+					set("text", requirement.Text).
+					set("recommendation", requirement.Recommendation).
+					set("applicability", strings.Join(requirement.Applicability, ", "))
+
+				for _, parameter := range requirement.RecommendedParameters {
+					s.add(KindParameter, parameter.Id).
+						set("description", parameter.Description).
+						set("default", utils.ConvertToString(parameter.Default))
+				}
+			}
+
+			var mappings []mappingEntry
+			for _, mapping := range control.GuidelineMappings {
+				for _, identifier := range mapping.Identifiers {
+					mappings = append(mappings, mappingEntry{mapping.ReferenceId, identifier})
+				}
+			}
+			addMappings(s, control.Id, mappings)
+		}
+	}
+	return s
+}
+
+type mappingEntry struct {
+	referenceId string
+	identifier  string
+}
+
+func guidelineMappings(mappings []layer1.Mapping) []mappingEntry {
+	var entries []mappingEntry
+	for _, mapping := range mappings {
+		for _, identifier := range mapping.Identifiers {
+			entries = append(entries, mappingEntry{mapping.ReferenceId, identifier})
+		}
+	}
+	return entries
+}
+
+// addMappings records each mapping as an element without fields, so mappings are only
+// ever reported as added or removed.
+func addMappings(s *snapshot, sourceId string, mappings []mappingEntry) {
+	for _, mapping := range mappings {
+		s.add(KindMapping, fmt.Sprintf("%s -> %s/%s", sourceId, mapping.referenceId, mapping.identifier))
+	}
+}
+
+func oscalSnapshot(catalog oscalTypes.Catalog) *snapshot {
+	s := newSnapshot()
+	resources := resourceIds(catalog.BackMatter)
+	if catalog.Params != nil {
+		addOSCALParams(s, *catalog.Params)
+	}
+	if catalog.Controls != nil {
+		addOSCALControls(s, *catalog.Controls, resources)
+	}
+	if catalog.Groups != nil {
+		addOSCALGroups(s, *catalog.Groups, resources)
+	}
+	return s
+}
+
+// resourceIds maps back-matter resource UUID fragments to the fragment of their id prop.
+func resourceIds(backMatter *oscalTypes.BackMatter) map[string]string {
+	resources := make(map[string]string)
+	if backMatter == nil || backMatter.Resources == nil {
+		return resources
+	}
+	for _, resource := range *backMatter.Resources {
+		if resource.Props == nil {
+			continue
+		}
+		for _, prop := range *resource.Props {
+			if prop.Name == "id" {
+				resources["#"+resource.UUID] = "#" + prop.Value
+			}
+		}
+	}
+	return resources
+}
+
+func addOSCALGroups(s *snapshot, groups []oscalTypes.Group, resources map[string]string) {
+	for _, group := range groups {
+		if group.Params != nil {
+			addOSCALParams(s, *group.Params)
+		}
+		if group.Controls != nil {
+			addOSCALControls(s, *group.Controls, resources)
+		}
+		if group.Groups != nil {
+			addOSCALGroups(s, *group.Groups, resources)
+		}
+	}
+}
+
+func addOSCALControls(s *snapshot, controls []oscalTypes.Control, resources map[string]string) {
+	for _, control := range controls {
+		element := s.add(KindControl, control.ID).set("title", control.Title)
+		if control.Parts != nil {
+			addOSCALParts(element, *control.Parts)
+		}
+		if control.Params != nil {
+			addOSCALParams(s, *control.Params)
+		}
+		if control.Links != nil {
+			for _, link := range *control.Links {
+				href := link.Href
+				if id, found := resources[href]; found {
+					href = id
+				}
+				s.add(KindLink, fmt.Sprintf("%s -> %s", control.ID, href))
+			}
+		}
+		if control.Controls != nil {
+			addOSCALControls(s, *control.Controls, resources)
+		}
+	}
+}
+
+func addOSCALParts(element *element, parts []oscalTypes.Part) {
+	for _, part := range parts {
+		name := part.Name
+		if part.ID != "" {
+			name = fmt.Sprintf("part %s", part.ID)
+		}
+		if part.Prose != "" {
+			element.set(name, part.Prose)
+		}
+		if part.Parts != nil {
+			addOSCALParts(element, *part.Parts)
+		}
+	}
+}
+
+func addOSCALParams(s *snapshot, params []oscalTypes.Parameter) {
+	for _, param := range params {
+		element := s.add(KindParameter, param.ID).set("label", param.Label)
+		if param.Values != nil {
+			element.set("values", strings.Join(*param.Values, ", "))
+		}
+	}
+}
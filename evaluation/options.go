@@ -0,0 +1,39 @@
+package evaluation
+
+import (
+	"time"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+	"github.com/jpower432/gemara2oscal/vocabulary"
+)
+
+type options struct {
+	utils.Reproducibility
+	vocabulary vocabulary.Vocabulary
+}
+
+// Option configures assessment results generation.
+type Option func(opts *options)
+
+// WithDeterministicUUIDs makes repeated conversions of the same evaluations produce identical
+// assessment results. UUIDs are derived from Gemara identifiers and the last modified, start and
+// collected dates are the WithTimestamp time, the plan last modified date or the Unix epoch.
+func WithDeterministicUUIDs() Option {
+	return func(opts *options) {
+		opts.Deterministic = true
+	}
+}
+
+// WithTimestamp sets the time used for the assessment results dates in deterministic mode.
+func WithTimestamp(timestamp time.Time) Option {
+	return func(opts *options) {
+		opts.Timestamp = &timestamp
+	}
+}
+
+// WithVocabulary sets the vocabulary of the observation props. The default is vocabulary.Trestle.
+func WithVocabulary(vocab vocabulary.Vocabulary) Option {
+	return func(opts *options) {
+		opts.vocabulary = vocab
+	}
+}
@@ -4,23 +4,33 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"time"
 
-	"github.com/defenseunicorns/go-oscal/src/pkg/uuid"
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
-	"github.com/oscal-compass/oscal-sdk-go/extensions"
 	"github.com/oscal-compass/oscal-sdk-go/transformers"
 	"github.com/ossf/gemara/layer4"
 
 	"github.com/jpower432/gemara2oscal/internal/utils"
+	"github.com/jpower432/gemara2oscal/vocabulary"
 )
 
 // Adapted from https://github.com/oscal-compass/compliance-to-policy-go/blob/main/framework/actions/report.go
 
 const Resource = "resource"
 
-func ToAssessmentResults(ctx context.Context, planHref string, plan oscalTypes.AssessmentPlan, evaluations []layer4.ControlEvaluation) (*oscalTypes.AssessmentResults, error) {
+// ToAssessmentResults converts Layer 4 evaluations into OSCAL Assessment Results for the given
+// Assessment Plan. Findings are sorted by target id and back-matter resources are emitted in the
+// order they are first observed.
+func ToAssessmentResults(ctx context.Context, planHref string, plan oscalTypes.AssessmentPlan, evaluations []layer4.ControlEvaluation, opts ...Option) (*oscalTypes.AssessmentResults, error) {
+	options := options{vocabulary: vocabulary.Trestle}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	uuids := options.UUIDs()
+	collected := options.Now(plan.Metadata.LastModified)
+
 	// for each PVPResult.Observation create an OSCAL Observation
 	oscalObservations := make([]oscalTypes.Observation, 0)
 	oscalFindings := make([]oscalTypes.Finding, 0)
@@ -33,6 +43,7 @@ func ToAssessmentResults(ctx context.Context, planHref string, plan oscalTypes.A
 
 	// maps resource items to subject UUIDs
 	resourceItemMap := make(map[string]oscalTypes.Resource)
+	var resourceOrder []string
 
 	// Get all the control mappings based on the assessment plan activities
 	rulesByControls := make(map[string][]string)
@@ -54,7 +65,7 @@ func ToAssessmentResults(ctx context.Context, planHref string, plan oscalTypes.A
 
 	// Process into observations
 	for _, evaluation := range evaluations {
-		obs, err := observationsFromEvaluation(evaluation, subjectUuidMap)
+		obs, err := observationsFromEvaluation(planHref, evaluation, subjectUuidMap, uuids, collected, options.vocabulary)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert observation for check %v: %w", evaluation.Control_Id, err)
 		}
@@ -71,6 +82,23 @@ func ToAssessmentResults(ctx context.Context, planHref string, plan oscalTypes.A
 		return nil, errors.New("bug: assessment results should only have one result")
 	}
 
+	// The transformer always generates random UUIDs and uses the current time, so replace them
+	// when deterministic output is requested.
+	if options.Deterministic {
+		assessmentResults.UUID = uuids.Generate(planHref, "assessment-results")
+		assessmentResults.Metadata.LastModified = collected
+		assessmentResults.Results[0].UUID = uuids.Generate(planHref, "result")
+		assessmentResults.Results[0].Start = collected
+		for i := range *assessmentResults.Results[0].Observations {
+			obs := &(*assessmentResults.Results[0].Observations)[i]
+			// Observations without props are created by the transformer for checks without results
+			if obs.Props == nil {
+				obs.UUID = uuids.Generate(planHref, "observation", obs.Title)
+				obs.Collected = collected
+			}
+		}
+	}
+
 	// Create findings after initial observations are added to ensure only observations
 	// in-scope of the plan are checked for failure.
 	for _, obs := range *assessmentResults.Results[0].Observations {
@@ -79,7 +107,7 @@ func ToAssessmentResults(ctx context.Context, planHref string, plan oscalTypes.A
 		if obs.Props == nil {
 			continue
 		}
-		rule, found := extensions.GetTrestleProp(extensions.AssessmentRuleIdProp, *obs.Props)
+		rule, found := options.vocabulary.Get(options.vocabulary.AssessmentRuleId, obs.Props)
 		if !found {
 			continue
 		}
@@ -95,14 +123,15 @@ func ToAssessmentResults(ctx context.Context, planHref string, plan oscalTypes.A
 				if _, ok := resourceItemMap[subject.SubjectUuid]; !ok {
 					resource := generateResource(&subject)
 					resourceItemMap[subject.SubjectUuid] = resource
+					resourceOrder = append(resourceOrder, subject.SubjectUuid)
 				}
 
-				result, found := extensions.GetTrestleProp("result", *subject.Props)
+				result, found := options.vocabulary.Get(options.vocabulary.Result, subject.Props)
 				if !found {
 					continue
 				}
 				if result.Value != "passed" {
-					oscalFindings, err = generateFindings(oscalFindings, obs, targets)
+					oscalFindings, err = generateFindings(planHref, oscalFindings, obs, targets, uuids)
 					if err != nil {
 						return nil, fmt.Errorf("failed to create finding for check: %w", err)
 					}
@@ -112,13 +141,16 @@ func ToAssessmentResults(ctx context.Context, planHref string, plan oscalTypes.A
 		}
 	}
 
+	sort.SliceStable(oscalFindings, func(i, j int) bool {
+		return oscalFindings[i].Target.TargetId < oscalFindings[j].Target.TargetId
+	})
 	assessmentResults.Results[0].Findings = utils.NilIfEmpty(&oscalFindings)
 
 	if len(resourceItemMap) > 0 {
 		backmatter := oscalTypes.BackMatter{}
 		resources := make([]oscalTypes.Resource, 0, len(resourceItemMap))
-		for _, r := range resourceItemMap {
-			resources = append(resources, r)
+		for _, id := range resourceOrder {
+			resources = append(resources, resourceItemMap[id])
 		}
 		backmatter.Resources = &resources
 		assessmentResults.BackMatter = &backmatter
@@ -146,12 +178,12 @@ func getFindingForTarget(findings []oscalTypes.Finding, targetId string) *oscalT
 }
 
 // Generate OSCAL Findings for all non-passing controls in the OSCAL Observation
-func generateFindings(findings []oscalTypes.Finding, observation oscalTypes.Observation, targets []string) ([]oscalTypes.Finding, error) {
+func generateFindings(planHref string, findings []oscalTypes.Finding, observation oscalTypes.Observation, targets []string, uuids utils.UUIDGenerator) ([]oscalTypes.Finding, error) {
 	for _, targetId := range targets {
 		finding := getFindingForTarget(findings, targetId)
 		if finding == nil { // if an empty finding was returned, create a new one and append to findings
 			newFinding := oscalTypes.Finding{
-				UUID: uuid.NewUUID(),
+				UUID: uuids.Generate(planHref, "finding", targetId),
 				RelatedObservations: &[]oscalTypes.RelatedObservation{
 					{
 						ObservationUuid: observation.UUID,
@@ -176,7 +208,7 @@ func generateFindings(findings []oscalTypes.Finding, observation oscalTypes.Obse
 	return findings, nil
 }
 
-func observationsFromEvaluation(eval layer4.ControlEvaluation, subjectUUID map[string]string) ([]oscalTypes.Observation, error) {
+func observationsFromEvaluation(planHref string, eval layer4.ControlEvaluation, subjectUUID map[string]string, uuids utils.UUIDGenerator, collected time.Time, vocab vocabulary.Vocabulary) ([]oscalTypes.Observation, error) {
 	var observations []oscalTypes.Observation
 	for _, assessment := range eval.Assessments {
 		for _, method := range assessment.Methods {
@@ -190,7 +222,7 @@ func observationsFromEvaluation(eval layer4.ControlEvaluation, subjectUUID map[s
 			// Should be fixed with https://github.com/revanite-io/sci/issues/23
 			subjectUuid, ok := subjectUUID[assessment.Requirement_Id]
 			if !ok {
-				subjectUuid = uuid.NewUUID()
+				subjectUuid = uuids.Generate(planHref, "subject", assessment.Requirement_Id)
 				subjectUUID[assessment.Requirement_Id] = subjectUuid
 			}
 
@@ -215,45 +247,25 @@ func observationsFromEvaluation(eval layer4.ControlEvaluation, subjectUUID map[s
 				Title:       assessment.Message,
 				Type:        Resource,
 				Props: &[]oscalTypes.Property{
-					{
-						Name:  "result",
-						Value: resultString,
-						Ns:    extensions.TrestleNameSpace,
-					},
-					{
-						Name:  "reason",
-						Value: assessment.Message,
-						Ns:    extensions.TrestleNameSpace,
-					},
-					{
-						Name:  "steps-executed",
-						Value: strconv.Itoa(assessment.Steps_Executed),
-						Ns:    extensions.TrestleNameSpace,
-					},
+					vocab.Prop(vocab.Result, resultString),
+					vocab.Prop(vocab.Reason, assessment.Message),
+					vocab.Prop(vocab.StepsExecuted, strconv.Itoa(assessment.Steps_Executed)),
 				},
 			}
 
 			oscalObservation := oscalTypes.Observation{
-				UUID:        uuid.NewUUID(),
+				UUID:        uuids.Generate(planHref, "observation", eval.Control_Id, assessment.Requirement_Id, method.Name),
 				Title:       assessment.Requirement_Id,
 				Description: assessment.Description,
 				Methods:     []string{"TEST-AUTOMATED"},
 				// TODO: Think this conversion more since there is no L4 timestamp
-				Collected: time.Now(),
+				Collected: collected,
 				Subjects:  &[]oscalTypes.SubjectReference{subj},
 			}
 
 			oscalObservation.Props = &[]oscalTypes.Property{
-				{
-					Name:  extensions.AssessmentRuleIdProp,
-					Value: assessment.Requirement_Id,
-					Ns:    extensions.TrestleNameSpace,
-				},
-				{
-					Name:  extensions.AssessmentCheckIdProp,
-					Value: method.Name,
-					Ns:    extensions.TrestleNameSpace,
-				},
+				vocab.Prop(vocab.AssessmentRuleId, assessment.Requirement_Id),
+				vocab.Prop(vocab.AssessmentCheckId, method.Name),
 			}
 			observations = append(observations, oscalObservation)
 		}
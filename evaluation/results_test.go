@@ -3,6 +3,7 @@ package evaluation
 import (
 	"context"
 	"testing"
+	"time"
 
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/oscal-compass/oscal-sdk-go/validation"
@@ -87,3 +88,100 @@ func TestToAssessmentResults(t *testing.T) {
 	err = validator.Validate(oscalModels)
 	require.NoError(t, err)
 }
+
+func TestToAssessmentResults_Deterministic(t *testing.T) {
+	result := layer4.Failed
+	eval := layer4.ControlEvaluation{
+		Control_Id: "OSPS-QA-07",
+		Assessments: []*layer4.Assessment{
+			{
+				Requirement_Id: "OSPS-QA-07.01",
+				Message:        "Failure information",
+				Methods: []layer4.AssessmentMethod{
+					{
+						Name:        "my-check-id",
+						Description: "My method",
+						Result:      &result,
+					},
+				},
+			},
+		},
+	}
+
+	selection := []oscalTypes.AssessedControls{
+		{
+			IncludeControls: &[]oscalTypes.AssessedControlsSelectControlById{{ControlId: "pl-8"}},
+		},
+	}
+	plan := oscalTypes.AssessmentPlan{
+		Metadata: oscalTypes.Metadata{LastModified: time.Date(2025, 7, 26, 15, 0, 0, 0, time.UTC)},
+		LocalDefinitions: &oscalTypes.LocalDefinitions{
+			Activities: &[]oscalTypes.Activity{
+				{
+					UUID:            "example-uuid",
+					Title:           "OSPS-QA-07.01",
+					Steps:           &[]oscalTypes.Step{{Title: "my-check-id"}, {Title: "my-other-check-id"}},
+					RelatedControls: &oscalTypes.ReviewedControls{ControlSelections: selection},
+				},
+			},
+		},
+		Tasks: &[]oscalTypes.Task{
+			{
+				AssociatedActivities: &[]oscalTypes.AssociatedActivity{{ActivityUuid: "example-uuid"}},
+			},
+		},
+		ReviewedControls: oscalTypes.ReviewedControls{ControlSelections: selection},
+	}
+
+	first, err := ToAssessmentResults(context.Background(), "plan.json", plan, []layer4.ControlEvaluation{eval}, WithDeterministicUUIDs())
+	require.NoError(t, err)
+	second, err := ToAssessmentResults(context.Background(), "plan.json", plan, []layer4.ControlEvaluation{eval}, WithDeterministicUUIDs())
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+
+	require.Equal(t, plan.Metadata.LastModified, first.Metadata.LastModified)
+	require.Equal(t, plan.Metadata.LastModified, first.Results[0].Start)
+	observations := *first.Results[0].Observations
+	require.Len(t, observations, 2)
+	for _, observation := range observations {
+		require.Equal(t, plan.Metadata.LastModified, observation.Collected)
+	}
+	require.Len(t, *first.Results[0].Findings, 1)
+
+	timestamp := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	ar, err := ToAssessmentResults(context.Background(), "plan.json", plan, []layer4.ControlEvaluation{eval}, WithDeterministicUUIDs(), WithTimestamp(timestamp))
+	require.NoError(t, err)
+	require.Equal(t, timestamp, ar.Metadata.LastModified)
+
+	// Results for another plan share no UUIDs
+	other, err := ToAssessmentResults(context.Background(), "other-plan.json", plan, []layer4.ControlEvaluation{eval}, WithDeterministicUUIDs())
+	require.NoError(t, err)
+	firstUUIDs := resultUUIDs(first)
+	for uuid := range resultUUIDs(other) {
+		require.False(t, firstUUIDs[uuid], "UUID %s is reused across plans", uuid)
+	}
+}
+
+// resultUUIDs returns the UUIDs of the assessment results, its observations, subjects and findings.
+func resultUUIDs(ar *oscalTypes.AssessmentResults) map[string]bool {
+	uuids := map[string]bool{ar.UUID: true}
+	for _, result := range ar.Results {
+		uuids[result.UUID] = true
+		if result.Observations != nil {
+			for _, observation := range *result.Observations {
+				uuids[observation.UUID] = true
+				if observation.Subjects != nil {
+					for _, subject := range *observation.Subjects {
+						uuids[subject.SubjectUuid] = true
+					}
+				}
+			}
+		}
+		if result.Findings != nil {
+			for _, finding := range *result.Findings {
+				uuids[finding.UUID] = true
+			}
+		}
+	}
+	return uuids
+}
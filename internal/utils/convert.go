@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ConvertToString converts a Gemara parameter value into its string form.
+func ConvertToString(val any) string {
+	if val == nil {
+		return ""
+	}
+	switch v := val.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	default:
+		return fmt.Sprint(v)
+	}
+}
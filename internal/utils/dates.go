@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"strings"
+	"time"
+)
+
+// DateLayouts are the accepted layouts for Gemara metadata dates in the order they are tried.
+// Partial dates (e.g. 2022-05 or 2022) resolve to the start of the month or year.
+var DateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	time.DateTime,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	time.DateOnly,
+	"2006/01/02",
+	"2006-01",
+	"2006",
+}
+
+// ParseDate parses a metadata date with the first matching layout. Dates without a time zone are
+// interpreted as UTC. The second return value is false when the value is empty or does not match
+// any layout.
+func ParseDate(value string, layouts []string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -7,15 +7,16 @@ import (
 
 // Assisted by: Gemini 2.5 Flash
 
-func NormalizeControl(input string) string {
-	// Compile the regular expression to find patterns like (number).
-	// \( and \) are used to match literal parentheses.
-	// (\d+) captures one or more digits inside the parentheses.
-	re := regexp.MustCompile(`\((\d+)\)`)
+// enhancementRe finds patterns like (number).
+// \( and \) are used to match literal parentheses.
+// (\d+) captures one or more digits inside the parentheses.
+// Lettered parts such as (a) identify control statements, not enhancements, and are left as is.
+var enhancementRe = regexp.MustCompile(`\((\d+)\)`)
 
+func NormalizeControl(input string) string {
 	// Replace all occurrences of the pattern.
 	// ".$1" means replace with a dot followed by the content of the first captured group (the digits).
-	replacedString := re.ReplaceAllString(input, ".$1")
+	replacedString := enhancementRe.ReplaceAllString(input, ".$1")
 
 	// Convert the entire resulting string to lowercase.
 	finalString := strings.ToLower(replacedString)
@@ -0,0 +1,39 @@
+package utils
+
+import "time"
+
+// Reproducibility configures reproducible output. It is embedded in the options of the converters.
+type Reproducibility struct {
+	// Deterministic enables name-based UUIDs and fixed timestamps.
+	Deterministic bool
+	// Timestamp is used for generated timestamps in deterministic mode.
+	Timestamp *time.Time
+}
+
+// UUIDs returns the UUIDGenerator for the configured mode.
+func (r Reproducibility) UUIDs() UUIDGenerator {
+	return NewUUIDGenerator(r.Deterministic)
+}
+
+// Now returns the time for generated timestamps (e.g. a last modified or collected date). Outside
+// deterministic mode this is the current time. In deterministic mode it is the configured Timestamp,
+// the latest of the given source dates (e.g. the last modified dates of the source documents) or,
+// when neither is available, the Unix epoch.
+func (r Reproducibility) Now(sourceDates ...time.Time) time.Time {
+	if !r.Deterministic {
+		return time.Now()
+	}
+	if r.Timestamp != nil {
+		return *r.Timestamp
+	}
+	var latest time.Time
+	for _, date := range sourceDates {
+		if date.After(latest) {
+			latest = date
+		}
+	}
+	if latest.IsZero() {
+		return time.Unix(0, 0).UTC()
+	}
+	return latest
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ToToken converts a free-form string (e.g. a title) into a valid OSCAL token
+// by lowercasing it and replacing unsupported characters with a hyphen.
+func ToToken(input string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(input)) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '.', r == '_', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	token := b.String()
+	// Tokens must start with a letter or underscore
+	if first := []rune(token); len(first) == 0 || !(unicode.IsLetter(first[0]) || first[0] == '_') {
+		token = "_" + token
+	}
+	return token
+}
+
+var tokenRe = regexp.MustCompile(`^(\p{L}|_)(\p{L}|\p{N}|[.\-_])*$`)
+
+// IsToken reports whether the input is a valid OSCAL token.
+func IsToken(input string) bool {
+	return tokenRe.MatchString(input)
+}
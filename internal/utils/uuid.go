@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/defenseunicorns/go-oscal/src/pkg/uuid"
+)
+
+// UUIDGenerator creates UUIDs for OSCAL objects.
+type UUIDGenerator struct {
+	deterministic bool
+}
+
+// NewUUIDGenerator returns a UUIDGenerator. When deterministic is true, generated
+// UUIDs are name-based (v5) and derived from the source identifiers passed to Generate
+// so the same inputs always produce the same UUIDs.
+func NewUUIDGenerator(deterministic bool) UUIDGenerator {
+	return UUIDGenerator{deterministic: deterministic}
+}
+
+// Generate returns a new UUID. The source identifiers are only used in deterministic mode
+// and should uniquely identify the object the UUID is created for.
+func (g UUIDGenerator) Generate(source ...string) string {
+	if !g.deterministic {
+		return uuid.NewUUID()
+	}
+	return uuid.NewUUIDWithSource(strings.Join(source, "/"))
+}
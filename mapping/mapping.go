@@ -0,0 +1,142 @@
+package mapping
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/oscal-compass/oscal-sdk-go/models"
+	"github.com/ossf/gemara/layer2"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+	"github.com/jpower432/gemara2oscal/normalize"
+)
+
+const (
+	// ItemTypeControl is the map item type for controls.
+	ItemTypeControl = "control"
+	// ResourceTypeCatalog is the resource type for OSCAL catalogs.
+	ResourceTypeCatalog = "catalog"
+)
+
+// ToMappingCollections converts the guideline mappings of a Layer 2 catalog into OSCAL mapping
+// collections, one per catalog mapping reference. Each Layer 2 control and mapped identifier
+// becomes a map from the control to the normalized identifier. Mapping references without any
+// guideline mappings are omitted.
+//
+// A *normalize.CollisionError is returned when distinct control ids or distinct identifiers of a
+// mapping reference normalize to the same OSCAL id. Every collection is checked with Validate, so
+// an error is also returned when an identifier does not normalize to a valid OSCAL token (e.g. PCI
+// DSS 6.2.3.1 with the default normalizer). Use WithReferenceNormalizer for such references.
+func ToMappingCollections(catalog layer2.Catalog, opts ...Option) ([]MappingCollection, error) {
+	options := options{
+		relationship: IntersectsWith,
+		sourceHref:   catalog.Metadata.Id,
+		normalizer:   normalize.NIST80053,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	uuids := options.UUIDs()
+	catalogModified, _ := utils.ParseDate(catalog.Metadata.LastModified, utils.DateLayouts)
+	lastModified := options.Now(catalogModified)
+
+	references := catalog.Metadata.MappingReferences
+	declared := make(map[string]bool, len(references))
+	for _, reference := range references {
+		declared[reference.Id] = true
+	}
+
+	var controlIds []string
+	for _, family := range catalog.ControlFamilies {
+		for _, control := range family.Controls {
+			controlIds = append(controlIds, control.Id)
+		}
+	}
+	if err := normalize.Check(catalog.Metadata.Id, options.normalizer, controlIds); err != nil {
+		return nil, err
+	}
+
+	mapsByReference := make(map[string][]Map)
+	identifiers := make(map[string][]string)
+	for _, family := range catalog.ControlFamilies {
+		for _, control := range family.Controls {
+			sourceId := options.normalizer.Normalize(control.Id)
+			for _, guidelineMapping := range control.GuidelineMappings {
+				referenceId := guidelineMapping.ReferenceId
+				if !declared[referenceId] {
+					if !options.undeclared {
+						continue
+					}
+					declared[referenceId] = true
+					references = append(references, layer2.MappingReference{Id: referenceId, Title: referenceId})
+				}
+				normalizer := options.references.For(referenceId)
+				identifiers[referenceId] = append(identifiers[referenceId], guidelineMapping.Identifiers...)
+				for _, identifier := range guidelineMapping.Identifiers {
+					targetId := normalizer.Normalize(identifier)
+					mapsByReference[referenceId] = append(mapsByReference[referenceId], Map{
+						UUID:         uuids.Generate(catalog.Metadata.Id, referenceId, sourceId, targetId),
+						Relationship: options.relationship,
+						Sources:      []Item{{Type: ItemTypeControl, IdRef: sourceId}},
+						Targets:      []Item{{Type: ItemTypeControl, IdRef: targetId}},
+					})
+				}
+			}
+		}
+	}
+
+	var collections []MappingCollection
+	for _, reference := range references {
+		maps := mapsByReference[reference.Id]
+		if len(maps) == 0 {
+			continue
+		}
+		if err := normalize.Check(reference.Id, options.references.For(reference.Id), identifiers[reference.Id]); err != nil {
+			return nil, err
+		}
+
+		metadata := models.NewSampleMetadata()
+		metadata.Title = fmt.Sprintf("%s to %s", catalog.Metadata.Title, reference.Title)
+		metadata.LastModified = lastModified
+		if catalog.Metadata.Version != "" {
+			metadata.Version = catalog.Metadata.Version
+		}
+
+		targetHref := reference.Url
+		if targetHref == "" {
+			targetHref = reference.Id
+		}
+
+		collection := MappingCollection{
+			UUID:     uuids.Generate(catalog.Metadata.Id, reference.Id),
+			Metadata: metadata,
+			Provenance: Provenance{
+				Method:             "human",
+				MatchingRationale:  "semantic",
+				Status:             "draft",
+				MappingDescription: strings.TrimSpace(fmt.Sprintf("Guideline mappings from %s to %s %s", catalog.Metadata.Id, reference.Id, reference.Version)),
+			},
+			Mappings: []Mapping{
+				{
+					UUID: uuids.Generate(catalog.Metadata.Id, reference.Id, "mapping"),
+					SourceResource: ResourceReference{
+						Type:  ResourceTypeCatalog,
+						Href:  options.sourceHref,
+						Title: catalog.Metadata.Title,
+					},
+					TargetResource: ResourceReference{
+						Type:  ResourceTypeCatalog,
+						Href:  targetHref,
+						Title: reference.Title,
+					},
+					Maps: maps,
+				},
+			},
+		}
+		if err := collection.Validate(); err != nil {
+			return nil, fmt.Errorf("mapping reference %s: %w", reference.Id, err)
+		}
+		collections = append(collections, collection)
+	}
+	return collections, nil
+}
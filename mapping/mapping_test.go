@@ -0,0 +1,105 @@
+package mapping
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/ossf/gemara/layer2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpower432/gemara2oscal/normalize"
+)
+
+func TestToMappingCollections(t *testing.T) {
+	file, err := os.Open("../component/testdata/good-osps.yml")
+	require.NoError(t, err)
+
+	var catalog layer2.Catalog
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&catalog)
+	require.NoError(t, err)
+
+	collections, err := ToMappingCollections(catalog, WithDeterministicUUIDs())
+	require.NoError(t, err)
+	require.Len(t, collections, 1)
+
+	collection := collections[0]
+	require.Equal(t, "Guideline mappings from OSPS-B to 800-161 1.1", collection.Provenance.MappingDescription)
+	require.Len(t, collection.Mappings, 1)
+
+	mapping := collection.Mappings[0]
+	require.Equal(t, "OSPS-B", mapping.SourceResource.Href)
+	require.Equal(t, "https://csrc.nist.gov/pubs/sp/800/161/r1/upd1/final", mapping.TargetResource.Href)
+	require.Len(t, mapping.Maps, 5)
+	require.Equal(t, Map{
+		UUID:         mapping.Maps[0].UUID,
+		Relationship: IntersectsWith,
+		Sources:      []Item{{Type: ItemTypeControl, IdRef: "osps-qa-07"}},
+		Targets:      []Item{{Type: ItemTypeControl, IdRef: "ac-5"}},
+	}, mapping.Maps[0])
+
+	again, err := ToMappingCollections(catalog, WithDeterministicUUIDs())
+	require.NoError(t, err)
+	require.Equal(t, collections, again)
+	for _, collection := range collections {
+		require.NoError(t, collection.Validate())
+	}
+	require.Equal(t, time.Unix(0, 0).UTC(), collection.Metadata.LastModified)
+
+	collections, err = ToMappingCollections(catalog,
+		WithUndeclaredReferences(),
+		WithRelationship(SubsetOf),
+		WithReferenceNormalizer("PCIDSS", normalize.PCI),
+	)
+	require.NoError(t, err)
+	require.Len(t, collections, 5)
+	require.Equal(t, "BPB", collections[1].Mappings[0].TargetResource.Href)
+	require.Equal(t, SubsetOf, collections[1].Mappings[0].Maps[0].Relationship)
+	require.Equal(t, "PCIDSS", collections[4].Mappings[0].TargetResource.Href)
+	require.Equal(t, "pci-6.2.3.1", collections[4].Mappings[0].Maps[0].Targets[0].IdRef)
+
+	// PCI DSS requirement numbers are not valid tokens with the default normalizer
+	_, err = ToMappingCollections(catalog, WithUndeclaredReferences())
+	require.ErrorContains(t, err, `mapping reference PCIDSS: map`)
+	require.ErrorContains(t, err, `id-ref "6.2.3.1" is not a valid OSCAL token`)
+
+	// Keeping only the PCI DSS principal requirement makes 6.2.3.1 and 6.4.2 collide
+	truncate := normalize.RegexTable{Rules: []normalize.Rule{normalize.NewRule(`^(\d+)\..*$`, "pci-$1")}}
+	_, err = ToMappingCollections(catalog, WithUndeclaredReferences(), WithReferenceNormalizer("PCIDSS", truncate))
+	var collisionErr *normalize.CollisionError
+	require.ErrorAs(t, err, &collisionErr)
+	require.Equal(t, "PCIDSS", collisionErr.Scope)
+}
+
+func TestMappingCollection_Validate(t *testing.T) {
+	collection := MappingCollection{
+		UUID: "not-a-uuid",
+		Mappings: []Mapping{
+			{
+				UUID:           "0b8a6a2e-5f4c-4c3a-9f43-6f1f3c1d2e7a",
+				SourceResource: ResourceReference{Type: ResourceTypeCatalog, Href: "OSPS-B"},
+				TargetResource: ResourceReference{Type: ResourceTypeCatalog},
+				Maps: []Map{
+					{
+						UUID:         "5c0e6a1b-2f4d-4b8e-8c3a-1d2e3f4a5b6c",
+						Relationship: "related-to",
+						Sources:      []Item{{Type: ItemTypeControl, IdRef: "osps-qa-07"}},
+						Targets:      []Item{{Type: ItemTypeControl, IdRef: "6.4.2"}},
+					},
+				},
+			},
+		},
+	}
+	err := collection.Validate()
+	require.EqualError(t, err, `mapping-collection: invalid uuid "not-a-uuid"
+mapping-collection: metadata title is required
+provenance: method is required
+provenance: matching-rationale is required
+provenance: status is required
+provenance: mapping-description is required
+mapping 0b8a6a2e-5f4c-4c3a-9f43-6f1f3c1d2e7a: target-resource href is required
+map 5c0e6a1b-2f4d-4b8e-8c3a-1d2e3f4a5b6c: invalid relationship "related-to"
+map 5c0e6a1b-2f4d-4b8e-8c3a-1d2e3f4a5b6c: id-ref "6.4.2" is not a valid OSCAL token`)
+}
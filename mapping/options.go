@@ -0,0 +1,77 @@
+package mapping
+
+import (
+	"time"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+	"github.com/jpower432/gemara2oscal/normalize"
+)
+
+type options struct {
+	utils.Reproducibility
+	relationship Relationship
+	sourceHref   string
+	undeclared   bool
+	normalizer   normalize.Normalizer
+	references   normalize.Table
+}
+
+// Option configures mapping collection generation.
+type Option func(opts *options)
+
+// WithDeterministicUUIDs makes repeated conversions of a catalog produce identical mapping
+// collections. UUIDs are derived from Gemara identifiers and the last modified date is the
+// WithTimestamp time, the catalog last modified date or the Unix epoch.
+func WithDeterministicUUIDs() Option {
+	return func(opts *options) {
+		opts.Deterministic = true
+	}
+}
+
+// WithTimestamp sets the last modified date of the mapping collections in deterministic mode.
+func WithTimestamp(timestamp time.Time) Option {
+	return func(opts *options) {
+		opts.Timestamp = &timestamp
+	}
+}
+
+// WithRelationship sets the relationship used for every map of every generated collection.
+// Layer 2 mappings do not express a relationship type per identifier, so it cannot be set per
+// control or mapping reference. The default is intersects-with.
+func WithRelationship(relationship Relationship) Option {
+	return func(opts *options) {
+		opts.relationship = relationship
+	}
+}
+
+// WithSourceHref sets the href of the source resource (e.g. the location of the OSCAL catalog
+// generated from the Layer 2 catalog). The default is the Layer 2 catalog id.
+func WithSourceHref(href string) Option {
+	return func(opts *options) {
+		opts.sourceHref = href
+	}
+}
+
+// WithUndeclaredReferences creates mapping collections for reference ids that are used in
+// guideline mappings but not declared in the catalog mapping references.
+func WithUndeclaredReferences() Option {
+	return func(opts *options) {
+		opts.undeclared = true
+	}
+}
+
+// WithNormalizer sets the Normalizer used for the Layer 2 control ids of map sources. It should
+// match the Normalizer used to generate the source catalog. The default is normalize.NIST80053.
+func WithNormalizer(normalizer normalize.Normalizer) Option {
+	return func(opts *options) {
+		opts.normalizer = normalizer
+	}
+}
+
+// WithReferenceNormalizer sets the Normalizer used for the identifiers of map targets of the
+// given mapping reference. The default is normalize.NIST80053.
+func WithReferenceNormalizer(referenceId string, normalizer normalize.Normalizer) Option {
+	return func(opts *options) {
+		opts.references.Set(referenceId, normalizer)
+	}
+}
@@ -0,0 +1,73 @@
+package mapping
+
+import (
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+)
+
+// The OSCAL control mapping model was introduced after OSCAL 1.1.3, so it is not
+// available in go-oscal. The types below follow the mapping-collection model.
+
+// Relationship describes how a source control relates to a target control.
+type Relationship string
+
+const (
+	EquivalentTo   Relationship = "equivalent-to"
+	EqualTo        Relationship = "equal-to"
+	SubsetOf       Relationship = "subset-of"
+	SupersetOf     Relationship = "superset-of"
+	IntersectsWith Relationship = "intersects-with"
+	NoRelationship Relationship = "no-relationship"
+)
+
+// Model is the top-level OSCAL document containing a mapping collection.
+type Model struct {
+	MappingCollection *MappingCollection `json:"mapping-collection" yaml:"mapping-collection"`
+}
+
+// MappingCollection is a set of mappings between a source and target resource.
+type MappingCollection struct {
+	UUID       string                 `json:"uuid" yaml:"uuid"`
+	Metadata   oscalTypes.Metadata    `json:"metadata" yaml:"metadata"`
+	Provenance Provenance             `json:"provenance" yaml:"provenance"`
+	Mappings   []Mapping              `json:"mappings" yaml:"mappings"`
+	BackMatter *oscalTypes.BackMatter `json:"back-matter,omitempty" yaml:"back-matter,omitempty"`
+}
+
+// Provenance describes how the mappings were created.
+type Provenance struct {
+	Method             string `json:"method" yaml:"method"`
+	MatchingRationale  string `json:"matching-rationale" yaml:"matching-rationale"`
+	Status             string `json:"status" yaml:"status"`
+	MappingDescription string `json:"mapping-description" yaml:"mapping-description"`
+}
+
+// Mapping maps items from a source resource to a target resource.
+type Mapping struct {
+	UUID           string                 `json:"uuid" yaml:"uuid"`
+	SourceResource ResourceReference      `json:"source-resource" yaml:"source-resource"`
+	TargetResource ResourceReference      `json:"target-resource" yaml:"target-resource"`
+	Props          *[]oscalTypes.Property `json:"props,omitempty" yaml:"props,omitempty"`
+	Maps           []Map                  `json:"maps" yaml:"maps"`
+}
+
+// ResourceReference identifies the catalog or profile that map items belong to.
+type ResourceReference struct {
+	Type  string `json:"type" yaml:"type"`
+	Href  string `json:"href" yaml:"href"`
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+}
+
+// Map relates source items to target items.
+type Map struct {
+	UUID         string       `json:"uuid" yaml:"uuid"`
+	Relationship Relationship `json:"relationship" yaml:"relationship"`
+	Sources      []Item       `json:"sources" yaml:"sources"`
+	Targets      []Item       `json:"targets" yaml:"targets"`
+	Remarks      string       `json:"remarks,omitempty" yaml:"remarks,omitempty"`
+}
+
+// Item references a control or statement in a mapped resource.
+type Item struct {
+	Type  string `json:"type" yaml:"type"`
+	IdRef string `json:"id-ref" yaml:"id-ref"`
+}
@@ -0,0 +1,74 @@
+package mapping
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+)
+
+// The mapping-collection model is not part of the OSCAL 1.1.3 schemas shipped with go-oscal, so
+// collections are checked against the model constraints here.
+
+var uuidRe = regexp.MustCompile(`^[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[45][0-9A-Fa-f]{3}-[89ABab][0-9A-Fa-f]{3}-[0-9A-Fa-f]{12}$`)
+
+var relationships = []Relationship{EquivalentTo, EqualTo, SubsetOf, SupersetOf, IntersectsWith, NoRelationship}
+
+// Validate checks the collection against the constraints of the OSCAL mapping-collection model:
+// required fields, UUID and token formats and relationship values. All issues are returned
+// joined into a single error.
+func (m MappingCollection) Validate() error {
+	var errs []error
+	checkUUID := func(location, value string) {
+		if !uuidRe.MatchString(value) {
+			errs = append(errs, fmt.Errorf("%s: invalid uuid %q", location, value))
+		}
+	}
+	checkRequired := func(location, field, value string) {
+		if value == "" {
+			errs = append(errs, fmt.Errorf("%s: %s is required", location, field))
+		}
+	}
+
+	checkUUID("mapping-collection", m.UUID)
+	checkRequired("mapping-collection", "metadata title", m.Metadata.Title)
+	checkRequired("provenance", "method", m.Provenance.Method)
+	checkRequired("provenance", "matching-rationale", m.Provenance.MatchingRationale)
+	checkRequired("provenance", "status", m.Provenance.Status)
+	checkRequired("provenance", "mapping-description", m.Provenance.MappingDescription)
+	if len(m.Mappings) == 0 {
+		errs = append(errs, errors.New("mapping-collection: at least one mapping is required"))
+	}
+
+	for _, mapping := range m.Mappings {
+		location := fmt.Sprintf("mapping %s", mapping.UUID)
+		checkUUID(location, mapping.UUID)
+		checkRequired(location, "source-resource type", mapping.SourceResource.Type)
+		checkRequired(location, "source-resource href", mapping.SourceResource.Href)
+		checkRequired(location, "target-resource type", mapping.TargetResource.Type)
+		checkRequired(location, "target-resource href", mapping.TargetResource.Href)
+		if len(mapping.Maps) == 0 {
+			errs = append(errs, fmt.Errorf("%s: at least one map is required", location))
+		}
+
+		for _, entry := range mapping.Maps {
+			location := fmt.Sprintf("map %s", entry.UUID)
+			checkUUID(location, entry.UUID)
+			if !slices.Contains(relationships, entry.Relationship) {
+				errs = append(errs, fmt.Errorf("%s: invalid relationship %q", location, entry.Relationship))
+			}
+			if len(entry.Sources) == 0 || len(entry.Targets) == 0 {
+				errs = append(errs, fmt.Errorf("%s: sources and targets are required", location))
+			}
+			for _, item := range slices.Concat(entry.Sources, entry.Targets) {
+				checkRequired(location, "item type", item.Type)
+				if !utils.IsToken(item.IdRef) {
+					errs = append(errs, fmt.Errorf("%s: id-ref %q is not a valid OSCAL token", location, item.IdRef))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
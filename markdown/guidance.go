@@ -0,0 +1,201 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/ossf/gemara/layer1"
+
+	"github.com/jpower432/gemara2oscal/controls"
+	"github.com/jpower432/gemara2oscal/normalize"
+)
+
+// ReadGuidance reads the control Markdown files in dir and applies the edits to a copy of the
+// Layer 1 guidance document the catalog was generated from with controls.ToCatalog. Guideline titles,
+// objectives, recommendations, part prose and recommendations, see-also and external references and
+// category titles are updated. Edits that cannot be represented in Layer 1 are reported as warning
+// diagnostics together with the diagnostics from ReadCatalog.
+func ReadGuidance(dir string, catalog oscalTypes.Catalog, guidance layer1.GuidanceDocument, opts ...Option) (layer1.GuidanceDocument, []controls.Diagnostic, error) {
+	options := options{normalizer: normalize.NIST80053}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	updatedCatalog, diagnostics, err := ReadCatalog(dir, catalog)
+	if err != nil {
+		return layer1.GuidanceDocument{}, nil, err
+	}
+
+	var updated layer1.GuidanceDocument
+	if err := deepCopy(guidance, &updated); err != nil {
+		return layer1.GuidanceDocument{}, nil, err
+	}
+
+	reader := guidanceReader{
+		normalizer: options.normalizer,
+		controls:   make(map[string]oscalTypes.Control),
+		groups:     make(map[string]oscalTypes.Group),
+		resources:  make(map[string]string),
+	}
+	if updatedCatalog.Groups != nil {
+		reader.indexGroups(*updatedCatalog.Groups)
+	}
+	if updatedCatalog.Controls != nil {
+		reader.indexControls(*updatedCatalog.Controls)
+	}
+	if updatedCatalog.BackMatter != nil && updatedCatalog.BackMatter.Resources != nil {
+		for _, resource := range *updatedCatalog.BackMatter.Resources {
+			if resource.Props == nil {
+				continue
+			}
+			for _, prop := range *resource.Props {
+				if prop.Name == "id" {
+					reader.resources[resource.UUID] = prop.Value
+				}
+			}
+		}
+	}
+
+	for i := range updated.Categories {
+		category := &updated.Categories[i]
+		if group, found := reader.groups[category.Id]; found {
+			category.Title = group.Title
+		}
+		for j := range category.Guidelines {
+			reader.applyGuideline(&category.Guidelines[j])
+		}
+	}
+
+	return updated, append(diagnostics, reader.diagnostics...), nil
+}
+
+type guidanceReader struct {
+	normalizer normalize.Normalizer
+	controls   map[string]oscalTypes.Control
+	groups     map[string]oscalTypes.Group
+	// resources maps back-matter resource UUIDs to Layer 1 resource ids
+	resources   map[string]string
+	diagnostics []controls.Diagnostic
+}
+
+func (r *guidanceReader) warn(location, format string, args ...any) {
+	r.diagnostics = append(r.diagnostics, controls.Diagnostic{
+		Severity: controls.SeverityWarning,
+		Location: location,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (r *guidanceReader) indexGroups(groups []oscalTypes.Group) {
+	for _, group := range groups {
+		r.groups[group.ID] = group
+		if group.Controls != nil {
+			r.indexControls(*group.Controls)
+		}
+		if group.Groups != nil {
+			r.indexGroups(*group.Groups)
+		}
+	}
+}
+
+func (r *guidanceReader) indexControls(controlList []oscalTypes.Control) {
+	for _, control := range controlList {
+		r.controls[control.ID] = control
+		if control.Controls != nil {
+			r.indexControls(*control.Controls)
+		}
+	}
+}
+
+func (r *guidanceReader) applyGuideline(guideline *layer1.Guideline) {
+	controlId := r.normalizer.Normalize(guideline.Id)
+	control, found := r.controls[controlId]
+	if !found {
+		return
+	}
+
+	guideline.Title = edited(guideline.Title, control.Title)
+
+	items := make(map[string]oscalTypes.Part)
+	if control.Parts != nil {
+		for _, part := range *control.Parts {
+			switch part.Name {
+			case "statement":
+				if part.Prose != "" {
+					r.warn(guideline.Id, "statement prose is not represented in Layer 1")
+				}
+				if part.Parts != nil {
+					for _, item := range *part.Parts {
+						items[item.ID] = item
+					}
+				}
+			case "assessment-objective":
+				guideline.Objective = edited(guideline.Objective, part.Prose)
+			case "guidance":
+				guideline.Recommendations = r.recommendations(guideline.Id, guideline.Recommendations, part.Prose)
+			}
+		}
+	}
+
+	for i := range guideline.GuidelineParts {
+		part := &guideline.GuidelineParts[i]
+		item, found := items[fmt.Sprintf("%s_smt.%s", controlId, part.Id)]
+		if !found {
+			continue
+		}
+		part.Prose = edited(part.Prose, item.Prose)
+		part.Recommendations = r.recommendations(fmt.Sprintf("%s part %s", guideline.Id, part.Id), part.Recommendations, joinGuidance(item))
+	}
+
+	r.applyLinks(guideline, control)
+}
+
+// applyLinks rebuilds the see-also and external references from the control links, keeping the
+// original spelling of guideline ids that were normalized for OSCAL.
+func (r *guidanceReader) applyLinks(guideline *layer1.Guideline, control oscalTypes.Control) {
+	seeAlso := make(map[string]string, len(guideline.SeeAlso))
+	for _, also := range guideline.SeeAlso {
+		seeAlso[r.normalizer.Normalize(also)] = also
+	}
+
+	var updatedSeeAlso, references []string
+	if control.Links != nil {
+		for _, link := range *control.Links {
+			target, isFragment := strings.CutPrefix(link.Href, "#")
+			switch {
+			case isFragment && link.Rel == "related":
+				if original, found := seeAlso[target]; found {
+					target = original
+				}
+				updatedSeeAlso = append(updatedSeeAlso, target)
+			case isFragment && link.Rel == "reference":
+				if resourceId, found := r.resources[target]; found {
+					target = resourceId
+				}
+				references = append(references, target)
+			default:
+				r.warn(guideline.Id, "link %q with rel %q is not represented in Layer 1", link.Href, link.Rel)
+			}
+		}
+	}
+	guideline.SeeAlso = updatedSeeAlso
+	guideline.ExternalReferences = references
+}
+
+// recommendations returns the original recommendations when the joined prose was not edited.
+// Edited prose becomes a single recommendation, since the joined prose cannot be split back into
+// the original recommendations. This is reported when there was more than one.
+func (r *guidanceReader) recommendations(location string, original []string, prose string) []string {
+	switch {
+	case strings.TrimSpace(strings.Join(original, " ")) == strings.TrimSpace(prose):
+		return original
+	case prose == "":
+		return nil
+	default:
+		if len(original) > 1 {
+			r.warn(location, "%d edited recommendations were merged into one recommendation", len(original))
+		}
+		return []string{prose}
+	}
+}
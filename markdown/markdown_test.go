@@ -0,0 +1,154 @@
+package markdown
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/ossf/gemara/layer1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jpower432/gemara2oscal/controls"
+)
+
+func loadGuidance(t *testing.T) layer1.GuidanceDocument {
+	file, err := os.Open("../controls/testdata/800-161.yml")
+	require.NoError(t, err)
+	defer file.Close()
+
+	var guidance layer1.GuidanceDocument
+	decoder := yaml.NewDecoder(file)
+	err = decoder.Decode(&guidance)
+	require.NoError(t, err)
+
+	// Add statement parts to exercise statement items and item guidance
+	guidance.Categories[0].Guidelines[0].GuidelineParts = []layer1.Part{
+		{
+			Id:              "a",
+			Prose:           "Identify duties that require separation.",
+			Recommendations: []string{"Document the duties in the access control policy."},
+		},
+		{
+			Id:    "b",
+			Prose: "Define system access authorizations to support separation of duties.",
+		},
+	}
+	return guidance
+}
+
+func TestWriteCatalog(t *testing.T) {
+	guidance := loadGuidance(t)
+	catalog, err := controls.ToCatalog(guidance)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, WriteCatalog(catalog, dir))
+
+	content, err := os.ReadFile(filepath.Join(dir, "AC", "ac-5.md"))
+	require.NoError(t, err)
+	want := `---
+links:
+- href: "#` + (*catalog.BackMatter.Resources)[0].UUID + `"
+  rel: reference
+---
+
+# ac-5 - \[Access Control\] Separation of Duties
+
+## Control Statement
+
+- \[a\] Identify duties that require separation.
+- \[b\] Define system access authorizations to support separation of duties.
+
+### Guidance \[a\]
+
+Document the duties in the access control policy.
+
+## Control assessment-objective
+
+Ensure that an appropriate separation of duties is established for decisions that require the acquisition of both information system and supply chain components.
+
+## Control guidance
+
+` + strings.Join(guidance.Categories[0].Guidelines[0].Recommendations, " ") + "\n"
+	require.Equal(t, want, string(content))
+
+	// Control enhancements are written next to their base control
+	require.FileExists(t, filepath.Join(dir, "AU", "au-6.9.md"))
+}
+
+func TestReadGuidance_Unchanged(t *testing.T) {
+	guidance := loadGuidance(t)
+	catalog, err := controls.ToCatalog(guidance)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, WriteCatalog(catalog, dir))
+
+	gotCatalog, diagnostics, err := ReadCatalog(dir, catalog)
+	require.NoError(t, err)
+	require.Empty(t, diagnostics)
+	require.Equal(t, catalog, gotCatalog)
+
+	gotGuidance, diagnostics, err := ReadGuidance(dir, catalog, guidance)
+	require.NoError(t, err)
+	require.Empty(t, diagnostics)
+	require.Equal(t, guidance, gotGuidance)
+}
+
+func TestReadGuidance_Edits(t *testing.T) {
+	guidance := loadGuidance(t)
+	catalog, err := controls.ToCatalog(guidance)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, WriteCatalog(catalog, dir))
+
+	path := filepath.Join(dir, "AC", "ac-5.md")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	edits := strings.NewReplacer(
+		`\[Access Control\] Separation of Duties`, `\[Access Management\] Separation of Duties and Roles`,
+		"Identify duties that require separation.", "Identify and document duties that require separation.",
+		"Document the duties in the access control policy.", "Review the duties annually.",
+		"- \\[b\\]", "- \\[c\\]",
+		"to relevant sub-tier contractors.", "to all sub-tier contractors.",
+		"## Control guidance", "## Control implementation\n\nNew section.\n\n## Control guidance",
+	)
+	require.NoError(t, os.WriteFile(path, []byte(edits.Replace(string(content))), 0600))
+
+	sa15 := filepath.Join(dir, "SA", "sa-15.md")
+	content, err = os.ReadFile(sa15)
+	require.NoError(t, err)
+	content = []byte(strings.Replace(string(content), "- href: \"#sa-15.7\"\n  rel: related\n", "", 1))
+	require.NoError(t, os.WriteFile(sa15, content, 0600))
+
+	got, diagnostics, err := ReadGuidance(dir, catalog, guidance)
+	require.NoError(t, err)
+
+	var messages []string
+	for _, diagnostic := range diagnostics {
+		require.Equal(t, controls.SeverityWarning, diagnostic.Severity)
+		messages = append(messages, diagnostic.Message)
+	}
+	require.Equal(t, []string{
+		`statement item "c" does not exist in control "ac-5" and was ignored`,
+		`statement item "b" was removed, removals are not mapped back`,
+		`section "implementation" has no matching part in control "ac-5" and was ignored`,
+		`2 edited recommendations were merged into one recommendation`,
+	}, messages)
+
+	require.Equal(t, "Access Management", got.Categories[0].Title)
+	ac5 := got.Categories[0].Guidelines[0]
+	require.Equal(t, "Separation of Duties and Roles", ac5.Title)
+	require.Equal(t, "Identify and document duties that require separation.", ac5.GuidelineParts[0].Prose)
+	require.Equal(t, []string{"Review the duties annually."}, ac5.GuidelineParts[0].Recommendations)
+	require.Equal(t, guidance.Categories[0].Guidelines[0].GuidelineParts[1], ac5.GuidelineParts[1])
+	require.Len(t, ac5.Recommendations, 1)
+	require.True(t, strings.HasSuffix(ac5.Recommendations[0], "to all sub-tier contractors."))
+	require.Equal(t, []string{"nist-sp-800-161r1"}, ac5.ExternalReferences)
+
+	sa := got.Categories[3].Guidelines[0]
+	require.Equal(t, []string{"SA-15(1)", "SA-15(2)", "SA-15(5)", "SA-15(6)"}, sa.SeeAlso)
+}
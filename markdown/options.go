@@ -0,0 +1,18 @@
+package markdown
+
+import "github.com/jpower432/gemara2oscal/normalize"
+
+type options struct {
+	normalizer normalize.Normalizer
+}
+
+// Option configures how Markdown edits are mapped back to Gemara documents.
+type Option func(opts *options)
+
+// WithNormalizer sets the Normalizer that was used to generate the catalog control ids from
+// guideline ids. The default is normalize.NIST80053.
+func WithNormalizer(normalizer normalize.Normalizer) Option {
+	return func(opts *options) {
+		opts.normalizer = normalizer
+	}
+}
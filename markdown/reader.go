@@ -0,0 +1,408 @@
+package markdown
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+
+	"github.com/jpower432/gemara2oscal/controls"
+	"github.com/jpower432/gemara2oscal/internal/utils"
+)
+
+var (
+	titleRe = regexp.MustCompile(`^# (\S+) - (?:\\\[(.*?)\\\] )?(.*)$`)
+	itemRe  = regexp.MustCompile(`^( *)- \\\[(.+?)\\\] ?(.*)$`)
+)
+
+// controlDocument is the parsed content of a control Markdown file.
+type controlDocument struct {
+	path       string
+	id         string
+	groupTitle string
+	title      string
+	links      []oscalTypes.Link
+	statement  string
+	items      []statementItem
+	// itemGuidance maps statement item labels to their guidance prose
+	itemGuidance map[string]string
+	// sections maps part names to their prose
+	sections map[string]string
+}
+
+type statementItem struct {
+	label string
+	prose string
+}
+
+// ReadCatalog reads the control Markdown files in dir written by WriteCatalog and applies the
+// edits to a copy of the catalog they were generated from. Titles, statement and item prose,
+// item guidance, top-level part prose, links and group titles are updated. Edits that cannot be
+// mapped back to the catalog (e.g. new controls, statement items or sections) are ignored and
+// reported as warning diagnostics.
+func ReadCatalog(dir string, catalog oscalTypes.Catalog) (oscalTypes.Catalog, []controls.Diagnostic, error) {
+	documents, err := readDocuments(dir)
+	if err != nil {
+		return oscalTypes.Catalog{}, nil, err
+	}
+
+	var updated oscalTypes.Catalog
+	if err := deepCopy(catalog, &updated); err != nil {
+		return oscalTypes.Catalog{}, nil, err
+	}
+	// Metadata is never edited and is kept as-is to preserve timestamp locations
+	updated.Metadata = catalog.Metadata
+
+	reader := catalogReader{
+		documents: documents,
+		applied:   make(map[string]bool),
+	}
+	if updated.Controls != nil {
+		reader.applyControls(*updated.Controls, nil)
+	}
+	if updated.Groups != nil {
+		reader.applyGroups(*updated.Groups)
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(documents)) {
+		if document := documents[id]; !reader.applied[id] {
+			reader.warn(document.path, "control %q does not exist in the catalog", document.id)
+		}
+	}
+	return updated, reader.diagnostics, nil
+}
+
+type catalogReader struct {
+	documents   map[string]controlDocument
+	applied     map[string]bool
+	diagnostics []controls.Diagnostic
+}
+
+func (r *catalogReader) warn(location, format string, args ...any) {
+	r.diagnostics = append(r.diagnostics, controls.Diagnostic{
+		Severity: controls.SeverityWarning,
+		Location: location,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (r *catalogReader) applyGroups(groups []oscalTypes.Group) {
+	for i := range groups {
+		group := &groups[i]
+		if group.Controls != nil {
+			r.applyControls(*group.Controls, group)
+		}
+		if group.Groups != nil {
+			r.applyGroups(*group.Groups)
+		}
+	}
+}
+
+func (r *catalogReader) applyControls(controlList []oscalTypes.Control, group *oscalTypes.Group) {
+	for i := range controlList {
+		control := &controlList[i]
+		if document, found := r.documents[control.ID]; found {
+			r.applied[control.ID] = true
+			r.applyGroupTitle(document, group)
+			r.applyControl(document, control)
+		}
+		if control.Controls != nil {
+			r.applyControls(*control.Controls, group)
+		}
+	}
+}
+
+// applyGroupTitle updates the group title from the control heading. The first edit wins and
+// conflicting edits from other controls in the same group are reported.
+func (r *catalogReader) applyGroupTitle(document controlDocument, group *oscalTypes.Group) {
+	if group == nil {
+		if document.groupTitle != "" {
+			r.warn(document.path, "control %q is not in a group, group title %q was ignored", document.id, document.groupTitle)
+		}
+		return
+	}
+	if document.groupTitle == "" || document.groupTitle == group.Title {
+		return
+	}
+	titleKey := fmt.Sprintf("group:%s", group.ID)
+	if r.applied[titleKey] {
+		r.warn(document.path, "group %q title %q conflicts with an earlier edit and was ignored", group.ID, document.groupTitle)
+		return
+	}
+	r.applied[titleKey] = true
+	group.Title = document.groupTitle
+}
+
+func (r *catalogReader) applyControl(document controlDocument, control *oscalTypes.Control) {
+	control.Title = document.title
+
+	links := document.links
+	control.Links = utils.NilIfEmpty(&links)
+
+	if control.Parts == nil {
+		control.Parts = &[]oscalTypes.Part{}
+	}
+
+	sections := make(map[string]bool, len(document.sections))
+	for i := range *control.Parts {
+		part := &(*control.Parts)[i]
+		if part.Name == "statement" {
+			r.applyStatement(document, control.ID, part)
+			continue
+		}
+		prose, found := document.sections[part.Name]
+		if !found {
+			r.warn(document.path, "section %q was removed, removals are not mapped back", part.Name)
+			continue
+		}
+		sections[part.Name] = true
+		part.Prose = edited(part.Prose, prose)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(document.sections)) {
+		if !sections[name] {
+			r.warn(document.path, "section %q has no matching part in control %q and was ignored", name, control.ID)
+		}
+	}
+
+	if len(*control.Parts) == 0 {
+		control.Parts = nil
+	}
+}
+
+func (r *catalogReader) applyStatement(document controlDocument, controlId string, statement *oscalTypes.Part) {
+	statement.Prose = edited(statement.Prose, document.statement)
+
+	var labels []string
+	items := make(map[string]*oscalTypes.Part)
+	if statement.Parts != nil {
+		labels = indexItems(controlId, *statement.Parts, items)
+	}
+
+	seen := make(map[string]bool, len(document.items))
+	for _, documentItem := range document.items {
+		item, found := items[documentItem.label]
+		if !found {
+			r.warn(document.path, "statement item %q does not exist in control %q and was ignored", documentItem.label, controlId)
+			continue
+		}
+		seen[documentItem.label] = true
+		item.Prose = edited(item.Prose, documentItem.prose)
+		applyItemGuidance(item, document.itemGuidance[documentItem.label])
+	}
+
+	for _, label := range slices.Sorted(maps.Keys(document.itemGuidance)) {
+		if !seen[label] {
+			r.warn(document.path, "guidance for statement item %q has no matching item and was ignored", label)
+		}
+	}
+
+	for _, label := range labels {
+		if !seen[label] {
+			r.warn(document.path, "statement item %q was removed, removals are not mapped back", label)
+		}
+	}
+}
+
+// applyItemGuidance updates the guidance sub-part of a statement item, adding one when the
+// item had no guidance and removing it when the guidance was deleted.
+func applyItemGuidance(item *oscalTypes.Part, prose string) {
+	var parts []oscalTypes.Part
+	var updated bool
+	if item.Parts != nil {
+		for _, subPart := range *item.Parts {
+			if subPart.Name == "guidance" {
+				if updated || prose == "" {
+					continue
+				}
+				subPart.Prose = edited(joinGuidance(*item), prose)
+				updated = true
+			}
+			parts = append(parts, subPart)
+		}
+	}
+	if prose != "" && !updated {
+		parts = append(parts, oscalTypes.Part{
+			Name:  "guidance",
+			ID:    fmt.Sprintf("%s_gdn", item.ID),
+			Prose: prose,
+		})
+	}
+	item.Parts = utils.NilIfEmpty(&parts)
+}
+
+// indexItems maps statement item labels to the items and returns the labels in document order.
+func indexItems(controlId string, parts []oscalTypes.Part, items map[string]*oscalTypes.Part) []string {
+	var labels []string
+	for i := range parts {
+		part := &parts[i]
+		if part.Name == "guidance" {
+			continue
+		}
+		label := itemLabel(controlId, *part)
+		items[label] = part
+		labels = append(labels, label)
+		if part.Parts != nil {
+			labels = append(labels, indexItems(controlId, *part.Parts, items)...)
+		}
+	}
+	return labels
+}
+
+// edited returns the original text when the Markdown text only differs in surrounding whitespace.
+func edited(original, text string) string {
+	if strings.TrimSpace(original) == text {
+		return original
+	}
+	return text
+}
+
+// readDocuments parses all Markdown files in dir keyed by control id.
+func readDocuments(dir string) (map[string]controlDocument, error) {
+	documents := make(map[string]controlDocument)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".md" {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		document, err := parseControl(string(content))
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		document.path = path
+		if document.id == "" {
+			document.id = strings.TrimSuffix(filepath.Base(path), ".md")
+		}
+		if existing, found := documents[document.id]; found {
+			return fmt.Errorf("control %q is defined in %s and %s", document.id, existing.path, path)
+		}
+		documents[document.id] = document
+		return nil
+	})
+	return documents, err
+}
+
+// parseState is the part of a control Markdown file currently being parsed.
+type parseState int
+
+const (
+	stateHeader parseState = iota
+	stateStatement
+	stateItem
+	stateItemGuidance
+	stateSection
+)
+
+func parseControl(content string) (controlDocument, error) {
+	document := controlDocument{
+		itemGuidance: make(map[string]string),
+		sections:     make(map[string]string),
+	}
+
+	body, err := parseFrontMatter(content, &document)
+	if err != nil {
+		return document, err
+	}
+
+	var (
+		state     = stateHeader
+		key       string
+		buffer    []string
+		itemDepth int
+	)
+
+	flush := func() {
+		text := strings.TrimSpace(strings.Join(buffer, "\n"))
+		buffer = nil
+		switch state {
+		case stateStatement:
+			document.statement = text
+		case stateItem:
+			document.items[len(document.items)-1].prose = text
+		case stateItemGuidance:
+			document.itemGuidance[key] = text
+		case stateSection:
+			document.sections[key] = text
+		}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case state == stateHeader && strings.HasPrefix(line, "# "):
+			matches := titleRe.FindStringSubmatch(line)
+			if matches == nil {
+				return document, fmt.Errorf("invalid control heading %q", line)
+			}
+			document.id, document.groupTitle, document.title = matches[1], matches[2], matches[3]
+		case line == statementHeading:
+			flush()
+			state = stateStatement
+		case strings.HasPrefix(line, sectionPrefix):
+			flush()
+			state, key = stateSection, strings.TrimPrefix(line, sectionPrefix)
+		case (state == stateStatement || state == stateItem || state == stateItemGuidance) && strings.HasPrefix(line, guidancePrefix):
+			flush()
+			label := strings.TrimPrefix(line, guidancePrefix)
+			state, key = stateItemGuidance, strings.TrimSuffix(strings.TrimPrefix(label, `\[`), `\]`)
+		case (state == stateStatement || state == stateItem) && itemRe.MatchString(line):
+			flush()
+			matches := itemRe.FindStringSubmatch(line)
+			state, itemDepth = stateItem, len(matches[1])/2
+			document.items = append(document.items, statementItem{label: matches[2]})
+			buffer = append(buffer, matches[3])
+		case state == stateItem:
+			// Continuation lines of an item are indented one level deeper than the item
+			buffer = append(buffer, strings.TrimPrefix(line, strings.Repeat("  ", itemDepth+1)))
+		default:
+			buffer = append(buffer, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return document, err
+	}
+	flush()
+	return document, nil
+}
+
+func parseFrontMatter(content string, document *controlDocument) (string, error) {
+	rest, found := strings.CutPrefix(content, "---\n")
+	if !found {
+		return content, nil
+	}
+	frontMatter, body, found := strings.Cut(rest, "\n---\n")
+	if !found {
+		return content, fmt.Errorf("unterminated front matter")
+	}
+	var h header
+	if err := yaml.Unmarshal([]byte(frontMatter), &h); err != nil {
+		return content, fmt.Errorf("invalid front matter: %w", err)
+	}
+	document.links = h.Links
+	return body, nil
+}
+
+// deepCopy copies src into dst so edits are never applied to the caller's values.
+func deepCopy(src, dst any) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
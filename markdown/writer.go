@@ -0,0 +1,184 @@
+package markdown
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+)
+
+const (
+	statementHeading = "## Control Statement"
+	sectionPrefix    = "## Control "
+	guidancePrefix   = "### Guidance "
+)
+
+// header is the YAML front matter of a control Markdown file.
+type header struct {
+	Links []oscalTypes.Link `yaml:"links,omitempty"`
+}
+
+// WriteCatalog writes one Markdown file per control in the compliance-trestle layout
+// (<dir>/<group-id>/<control-id>.md). Each file contains the control statement and its
+// items, item guidance, the remaining top-level parts as "Control <part name>" sections and the
+// control links in the front matter.
+func WriteCatalog(catalog oscalTypes.Catalog, dir string) error {
+	if catalog.Controls != nil {
+		if err := writeControls(*catalog.Controls, "", dir); err != nil {
+			return err
+		}
+	}
+	if catalog.Groups != nil {
+		if err := writeGroups(*catalog.Groups, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeGroups(groups []oscalTypes.Group, dir string) error {
+	for _, group := range groups {
+		groupDir := dir
+		if group.ID != "" {
+			groupDir = filepath.Join(dir, group.ID)
+		}
+		if group.Controls != nil {
+			if err := writeControls(*group.Controls, group.Title, groupDir); err != nil {
+				return err
+			}
+		}
+		if group.Groups != nil {
+			if err := writeGroups(*group.Groups, groupDir); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeControls writes the controls and their enhancements into the same directory.
+func writeControls(controls []oscalTypes.Control, groupTitle, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, control := range controls {
+		content, err := renderControl(control, groupTitle)
+		if err != nil {
+			return fmt.Errorf("failed to render control %s: %w", control.ID, err)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("%s.md", control.ID))
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return err
+		}
+		if control.Controls != nil {
+			if err := writeControls(*control.Controls, groupTitle, dir); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func renderControl(control oscalTypes.Control, groupTitle string) (string, error) {
+	var b strings.Builder
+
+	if control.Links != nil && len(*control.Links) > 0 {
+		frontMatter, err := yaml.Marshal(header{Links: *control.Links})
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "---\n%s---\n\n", frontMatter)
+	}
+
+	if groupTitle != "" {
+		fmt.Fprintf(&b, "# %s - \\[%s\\] %s\n\n", control.ID, groupTitle, control.Title)
+	} else {
+		fmt.Fprintf(&b, "# %s - %s\n\n", control.ID, control.Title)
+	}
+
+	if control.Parts == nil {
+		return b.String(), nil
+	}
+
+	for _, part := range *control.Parts {
+		if part.Name != "statement" {
+			continue
+		}
+		b.WriteString(statementHeading + "\n\n")
+		if part.Prose != "" {
+			fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(part.Prose))
+		}
+		if part.Parts != nil {
+			var guidance []oscalTypes.Part
+			writeItems(&b, control.ID, *part.Parts, 0, &guidance)
+			b.WriteString("\n")
+			for _, item := range guidance {
+				fmt.Fprintf(&b, "%s\\[%s\\]\n\n%s\n\n", guidancePrefix, itemLabel(control.ID, item), strings.TrimSpace(joinGuidance(item)))
+			}
+		}
+	}
+
+	for _, part := range *control.Parts {
+		if part.Name == "statement" {
+			continue
+		}
+		fmt.Fprintf(&b, "%s%s\n\n%s\n\n", sectionPrefix, part.Name, strings.TrimSpace(part.Prose))
+	}
+
+	return strings.TrimRight(b.String(), "\n") + "\n", nil
+}
+
+// writeItems writes statement items as a nested list and collects items with guidance.
+func writeItems(b *strings.Builder, controlId string, items []oscalTypes.Part, depth int, guidance *[]oscalTypes.Part) {
+	indent := strings.Repeat("  ", depth)
+	for _, item := range items {
+		if item.Name == "guidance" {
+			continue
+		}
+		lines := strings.Split(strings.TrimSpace(item.Prose), "\n")
+		fmt.Fprintf(b, "%s- \\[%s\\] %s\n", indent, itemLabel(controlId, item), lines[0])
+		for _, line := range lines[1:] {
+			if strings.TrimSpace(line) == "" {
+				b.WriteString("\n")
+				continue
+			}
+			fmt.Fprintf(b, "%s  %s\n", indent, line)
+		}
+
+		if joinGuidance(item) != "" {
+			*guidance = append(*guidance, item)
+		}
+		if item.Parts != nil {
+			writeItems(b, controlId, *item.Parts, depth+1, guidance)
+		}
+	}
+}
+
+// itemLabel returns the label prop of a statement item or the item id relative to the control statement.
+func itemLabel(controlId string, item oscalTypes.Part) string {
+	if item.Props != nil {
+		for _, prop := range *item.Props {
+			if prop.Name == "label" {
+				return prop.Value
+			}
+		}
+	}
+	return strings.TrimPrefix(item.ID, fmt.Sprintf("%s_smt.", controlId))
+}
+
+// joinGuidance returns the prose of all guidance sub-parts of an item.
+func joinGuidance(item oscalTypes.Part) string {
+	if item.Parts == nil {
+		return ""
+	}
+	var prose []string
+	for _, subPart := range *item.Parts {
+		if subPart.Name == "guidance" {
+			prose = append(prose, strings.TrimSpace(subPart.Prose))
+		}
+	}
+	return strings.Join(prose, "\n\n")
+}
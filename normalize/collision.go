@@ -0,0 +1,56 @@
+package normalize
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Collision is a set of distinct source identifiers that normalize to the same OSCAL id.
+type Collision struct {
+	OSCALId   string
+	SourceIds []string
+}
+
+// CollisionError is returned when distinct source identifiers normalize to the same OSCAL id.
+type CollisionError struct {
+	// Scope identifies the identifiers that were checked (e.g. a document or mapping reference id).
+	Scope      string
+	Collisions []Collision
+}
+
+func (c *CollisionError) Error() string {
+	collisions := make([]string, 0, len(c.Collisions))
+	for _, collision := range c.Collisions {
+		collisions = append(collisions, fmt.Sprintf("%s (from %s)", collision.OSCALId, strings.Join(collision.SourceIds, ", ")))
+	}
+	return fmt.Sprintf("%s: identifiers normalize to the same OSCAL id: %s", c.Scope, strings.Join(collisions, "; "))
+}
+
+// Check returns a *CollisionError when two distinct identifiers normalize to the same OSCAL id.
+// Repeated identical identifiers are not collisions.
+func Check(scope string, normalizer Normalizer, ids []string) error {
+	sources := make(map[string][]string)
+	for _, id := range ids {
+		oscalId := normalizer.Normalize(id)
+		if !slices.Contains(sources[oscalId], id) {
+			sources[oscalId] = append(sources[oscalId], id)
+		}
+	}
+
+	var collisions []Collision
+	for oscalId, sourceIds := range sources {
+		if len(sourceIds) > 1 {
+			sort.Strings(sourceIds)
+			collisions = append(collisions, Collision{OSCALId: oscalId, SourceIds: sourceIds})
+		}
+	}
+	if len(collisions) == 0 {
+		return nil
+	}
+	sort.Slice(collisions, func(i, j int) bool {
+		return collisions[i].OSCALId < collisions[j].OSCALId
+	})
+	return &CollisionError{Scope: scope, Collisions: collisions}
+}
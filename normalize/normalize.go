@@ -0,0 +1,101 @@
+package normalize
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jpower432/gemara2oscal/internal/utils"
+)
+
+// Normalizer converts a source control identifier (e.g. AC-2(1) or 6.2.3.1) into an OSCAL control id.
+type Normalizer interface {
+	Normalize(id string) string
+}
+
+// Func adapts an ordinary function to a Normalizer.
+type Func func(id string) string
+
+func (f Func) Normalize(id string) string {
+	return f(id)
+}
+
+var (
+	// NIST80053 converts NIST SP 800-53 style identifiers by replacing enhancement numbers in
+	// parentheses with a dot and lowercasing (e.g. AC-2(1) becomes ac-2.1). Lettered parts such
+	// as AC-2(1)(a) identify control statements and are not converted. This is the default.
+	NIST80053 Normalizer = Func(utils.NormalizeControl)
+
+	// PCI converts PCI DSS requirement numbers into OSCAL tokens by removing any "Req." or
+	// "Requirement" label, lowercasing and adding a pci- prefix (e.g. Req. 6.2.3.1 becomes pci-6.2.3.1).
+	PCI Normalizer = Func(pci)
+
+	// Passthrough returns identifiers unchanged. It is intended for identifiers that are already
+	// valid OSCAL tokens (e.g. Scorecard Code-Review).
+	Passthrough Normalizer = Func(func(id string) string { return id })
+)
+
+var pciLabelRe = regexp.MustCompile(`^(?i)req(uirement)?\.?\s*`)
+
+func pci(id string) string {
+	id = pciLabelRe.ReplaceAllString(strings.TrimSpace(id), "")
+	return "pci-" + strings.ToLower(strings.Join(strings.Fields(id), "-"))
+}
+
+// Rule replaces all matches of Pattern in an identifier with Replacement, which
+// may reference capture groups (e.g. $1).
+type Rule struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// NewRule returns a Rule for the pattern. It panics if the pattern does not compile.
+func NewRule(pattern, replacement string) Rule {
+	return Rule{
+		Pattern:     regexp.MustCompile(pattern),
+		Replacement: replacement,
+	}
+}
+
+// RegexTable is a Normalizer that applies its rules in order and optionally lowercases the result.
+// For example, CIS benchmark identifiers like 1.1.1 can be prefixed to form valid OSCAL tokens with
+// NewRule(`^(\d)`, "cis-$1").
+type RegexTable struct {
+	Rules     []Rule
+	Lowercase bool
+}
+
+func (r RegexTable) Normalize(id string) string {
+	for _, rule := range r.Rules {
+		id = rule.Pattern.ReplaceAllString(id, rule.Replacement)
+	}
+	if r.Lowercase {
+		id = strings.ToLower(id)
+	}
+	return id
+}
+
+// Table selects a Normalizer per mapping reference id.
+type Table struct {
+	// Default is used for references without an entry. When nil, NIST80053 is used.
+	Default    Normalizer
+	References map[string]Normalizer
+}
+
+// For returns the Normalizer for the mapping reference.
+func (t Table) For(referenceId string) Normalizer {
+	if normalizer, found := t.References[referenceId]; found {
+		return normalizer
+	}
+	if t.Default != nil {
+		return t.Default
+	}
+	return NIST80053
+}
+
+// Set sets the Normalizer for a mapping reference.
+func (t *Table) Set(referenceId string, normalizer Normalizer) {
+	if t.References == nil {
+		t.References = make(map[string]Normalizer)
+	}
+	t.References[referenceId] = normalizer
+}
@@ -0,0 +1,61 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizers(t *testing.T) {
+	cis := RegexTable{
+		Rules:     []Rule{NewRule(`^(\d)`, "cis-$1")},
+		Lowercase: true,
+	}
+
+	tests := []struct {
+		name       string
+		normalizer Normalizer
+		input      string
+		want       string
+	}{
+		{name: "NIST control", normalizer: NIST80053, input: "AC-5", want: "ac-5"},
+		{name: "NIST enhancement", normalizer: NIST80053, input: "SA-15(1)", want: "sa-15.1"},
+		{name: "NIST nested enhancement", normalizer: NIST80053, input: "AC-2(1)(2)", want: "ac-2.1.2"},
+		{name: "NIST lettered part", normalizer: NIST80053, input: "AC-2(1)(a)", want: "ac-2.1(a)"},
+		{name: "PCI requirement", normalizer: PCI, input: "6.2.3.1", want: "pci-6.2.3.1"},
+		{name: "PCI labelled requirement", normalizer: PCI, input: "Req. 6.4.2", want: "pci-6.4.2"},
+		{name: "PCI appendix", normalizer: PCI, input: "A1.1.1", want: "pci-a1.1.1"},
+		{name: "Passthrough", normalizer: Passthrough, input: "Code-Review", want: "Code-Review"},
+		{name: "Regex table", normalizer: cis, input: "1.1.1", want: "cis-1.1.1"},
+		{name: "Regex table no match", normalizer: cis, input: "L1.A", want: "l1.a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.normalizer.Normalize(tt.input))
+		})
+	}
+}
+
+func TestTable(t *testing.T) {
+	var table Table
+	require.Equal(t, "ac-5", table.For("800-161").Normalize("AC-5"))
+
+	table.Set("PCIDSS", PCI)
+	require.Equal(t, "pci-6.4.2", table.For("PCIDSS").Normalize("6.4.2"))
+
+	table.Default = Passthrough
+	require.Equal(t, "AC-5", table.For("800-161").Normalize("AC-5"))
+}
+
+func TestCheck(t *testing.T) {
+	require.NoError(t, Check("800-161", NIST80053, []string{"AC-5", "AU-6", "AU-6(9)", "AC-5"}))
+
+	err := Check("800-161", NIST80053, []string{"AC-5", "ac-5", "AU-6(9)", "AU-6.9", "PL-8"})
+	var collisionErr *CollisionError
+	require.ErrorAs(t, err, &collisionErr)
+	require.Equal(t, []Collision{
+		{OSCALId: "ac-5", SourceIds: []string{"AC-5", "ac-5"}},
+		{OSCALId: "au-6.9", SourceIds: []string{"AU-6(9)", "AU-6.9"}},
+	}, collisionErr.Collisions)
+	require.EqualError(t, err, "800-161: identifiers normalize to the same OSCAL id: ac-5 (from AC-5, ac-5); au-6.9 (from AU-6(9), AU-6.9)")
+}
@@ -0,0 +1,106 @@
+// Package vocabulary defines the property names and namespace used for the OSCAL props emitted
+// and read by the converters.
+package vocabulary
+
+import (
+	"fmt"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+)
+
+// GemaraNameSpace is the namespace for Gemara-native property extensions.
+const GemaraNameSpace = "https://github.com/ossf/gemara/ns/oscal"
+
+// Vocabulary names the props that link Gemara content across OSCAL models. Props are
+// emitted in the vocabulary Namespace, including props that are not named here (e.g.
+// catalog metadata props). Props that only describe Gemara selections, such as the
+// target component filter, are always emitted in GemaraNameSpace.
+type Vocabulary struct {
+	Namespace string
+
+	// RuleId and RuleDescription describe the rules of target components.
+	RuleId          string
+	RuleDescription string
+	// ParameterId, ParameterDescription and ParameterDefault describe the rule parameters.
+	// They are suffixed with the parameter index (e.g. Parameter_Id_0).
+	ParameterId          string
+	ParameterDescription string
+	ParameterDefault     string
+	// CheckId and CheckDescription describe the checks of validation components.
+	CheckId          string
+	CheckDescription string
+	// Framework is the mapping reference of a control implementation set.
+	Framework string
+
+	// AssessmentRuleId and AssessmentCheckId link observations to rules and checks.
+	AssessmentRuleId  string
+	AssessmentCheckId string
+	// Result, Reason and StepsExecuted describe the outcome for an observation subject.
+	Result        string
+	Reason        string
+	StepsExecuted string
+}
+
+// Trestle is the compliance-trestle vocabulary. It is the default.
+var Trestle = Vocabulary{
+	Namespace:            extensions.TrestleNameSpace,
+	RuleId:               extensions.RuleIdProp,
+	RuleDescription:      extensions.RuleDescriptionProp,
+	ParameterId:          extensions.ParameterIdProp,
+	ParameterDescription: extensions.ParameterDescriptionProp,
+	ParameterDefault:     extensions.ParameterDefaultProp,
+	CheckId:              extensions.CheckIdProp,
+	CheckDescription:     extensions.CheckDescriptionProp,
+	Framework:            extensions.FrameworkProp,
+	AssessmentRuleId:     extensions.AssessmentRuleIdProp,
+	AssessmentCheckId:    extensions.AssessmentCheckIdProp,
+	Result:               "result",
+	Reason:               "reason",
+	StepsExecuted:        "steps-executed",
+}
+
+// Gemara is a vocabulary named after the Gemara schema in the Gemara namespace.
+var Gemara = Vocabulary{
+	Namespace:            GemaraNameSpace,
+	RuleId:               "assessment-requirement-id",
+	RuleDescription:      "assessment-requirement-text",
+	ParameterId:          "parameter-id",
+	ParameterDescription: "parameter-description",
+	ParameterDefault:     "parameter-default",
+	CheckId:              "assessment-method",
+	CheckDescription:     "assessment-method-description",
+	Framework:            "mapping-reference",
+	AssessmentRuleId:     "assessment-requirement-id",
+	AssessmentCheckId:    "assessment-method",
+	Result:               "result",
+	Reason:               "message",
+	StepsExecuted:        "steps-executed",
+}
+
+// Prop returns a prop with the given name and value in the vocabulary namespace.
+func (v Vocabulary) Prop(name, value string) oscalTypes.Property {
+	return oscalTypes.Property{
+		Name:  name,
+		Value: value,
+		Ns:    v.Namespace,
+	}
+}
+
+// Indexed returns the name of an indexed prop (e.g. the ParameterId of the second parameter).
+func (v Vocabulary) Indexed(name string, index int) string {
+	return fmt.Sprintf("%s_%d", name, index)
+}
+
+// Get returns the first prop with the given name in the vocabulary namespace.
+func (v Vocabulary) Get(name string, props *[]oscalTypes.Property) (oscalTypes.Property, bool) {
+	if props == nil {
+		return oscalTypes.Property{}, false
+	}
+	for _, prop := range *props {
+		if prop.Name == name && prop.Ns == v.Namespace {
+			return prop, true
+		}
+	}
+	return oscalTypes.Property{}, false
+}
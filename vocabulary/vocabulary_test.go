@@ -0,0 +1,35 @@
+package vocabulary
+
+import (
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVocabulary_Get(t *testing.T) {
+	props := &[]oscalTypes.Property{
+		Trestle.Prop(Trestle.RuleId, "trestle-rule"),
+		Gemara.Prop(Gemara.RuleId, "gemara-rule"),
+		Gemara.Prop(Gemara.Indexed(Gemara.ParameterId, 1), "param"),
+	}
+
+	prop, found := Trestle.Get(Trestle.RuleId, props)
+	require.True(t, found)
+	require.Equal(t, oscalTypes.Property{Name: extensions.RuleIdProp, Value: "trestle-rule", Ns: extensions.TrestleNameSpace}, prop)
+
+	prop, found = Gemara.Get(Gemara.RuleId, props)
+	require.True(t, found)
+	require.Equal(t, "gemara-rule", prop.Value)
+
+	prop, found = Gemara.Get("parameter-id_1", props)
+	require.True(t, found)
+	require.Equal(t, "param", prop.Value)
+
+	// Props in another namespace are ignored
+	_, found = Trestle.Get(Trestle.ParameterId+"_1", props)
+	require.False(t, found)
+	_, found = Gemara.Get(Gemara.RuleId, nil)
+	require.False(t, found)
+}